@@ -0,0 +1,152 @@
+package migadu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultActivationInterval    = 15 * time.Second
+	DefaultActivationMaxInterval = 5 * time.Minute
+)
+
+// ActivationOptions configures WaitForDomainActivation. The zero value uses the defaults.
+type ActivationOptions struct {
+	// Interval is the delay after the first attempt. It doubles after every attempt up to MaxInterval.
+	Interval    time.Duration
+	MaxInterval time.Duration
+	// OnProgress, when set, is called after every attempt.
+	OnProgress func(ActivationProgress)
+}
+
+// ActivationProgress reports one attempt of WaitForDomainActivation.
+type ActivationProgress struct {
+	Attempt int
+	// Checks holds the diagnostics checks of this attempt and Failing the ones not passing.
+	// Both are empty when the domain was already active.
+	Checks  []DiagnosticCheck
+	Failing []DiagnosticCheck
+	// Domain is the active domain or the result of ActivateDomain, nil when neither was received.
+	Domain *Domain
+	// Err is the error of this attempt, if any, such as ActivateDomain rejected because
+	// the DNS records are not in place yet. Transient errors do not stop the wait.
+	Err error
+	// Next is the delay before the next attempt, zero after the last one.
+	Next time.Duration
+}
+
+// ActivationTimeoutError is returned when ctx ends before the domain becomes active.
+// It matches the context error through errors.Is.
+type ActivationTimeoutError struct {
+	Domain   string
+	Attempts int
+	// Failing holds the checks that were still failing in the last diagnostics received.
+	Failing []DiagnosticCheck
+	// LastErr is the error of the last attempt, if any.
+	LastErr error
+	Err     error
+}
+
+func (e *ActivationTimeoutError) Error() string {
+	var detail []string
+	for _, check := range e.Failing {
+		detail = append(detail, check.String())
+	}
+	if e.LastErr != nil {
+		detail = append(detail, e.LastErr.Error())
+	}
+	msg := fmt.Sprintf("domain %s not active after %d attempts: %v", e.Domain, e.Attempts, e.Err)
+	if len(detail) > 0 {
+		msg += " (" + strings.Join(detail, "; ") + ")"
+	}
+	return msg
+}
+
+func (e *ActivationTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// WaitForDomainActivation polls GetDomain until the domain state is DomainStateActive.
+// While it is not, every attempt calls ActivateDomain, which leaves it to Migadu to
+// decide whether the DNS is ready; GetDomainDiagnostics is only fetched to report
+// the checks that are not passing. It returns the active domain,
+// an *ActivationTimeoutError when ctx ends first, or the error of an attempt that
+// cannot succeed by waiting, such as a missing domain or rejected credentials.
+func (c *Client) WaitForDomainActivation(ctx context.Context, domain string, opts ActivationOptions) (*Domain, error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultActivationInterval
+	}
+	maxInterval := opts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultActivationMaxInterval
+	}
+	var failing []DiagnosticCheck
+	for attempt := 1; ; attempt++ {
+		progress, diagnosed := c.activationAttempt(ctx, domain)
+		progress.Attempt = attempt
+		if progress.Err != nil && isPermanentActivationError(progress.Err) {
+			return nil, progress.Err
+		}
+		if diagnosed {
+			failing = progress.Failing
+		}
+		active := progress.Domain != nil && progress.Domain.State == DomainStateActive
+		if !active && ctx.Err() == nil {
+			progress.Next = interval
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+		if active {
+			return progress.Domain, nil
+		}
+		if progress.Next == 0 || !waitRetry(ctx, interval) {
+			// waitRetry gives up early when the deadline falls before the next attempt.
+			err := ctx.Err()
+			if err == nil {
+				err = context.DeadlineExceeded
+			}
+			lastErr := progress.Err
+			if errors.Is(lastErr, err) {
+				// The attempt was cut short by ctx, which Err already reports.
+				lastErr = nil
+			}
+			return nil, &ActivationTimeoutError{Domain: domain, Attempts: attempt, Failing: failing, LastErr: lastErr, Err: err}
+		}
+		if interval *= 2; interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+// activationAttempt reports whether the diagnostics were received, even if activation then failed.
+func (c *Client) activationAttempt(ctx context.Context, domain string) (ActivationProgress, bool) {
+	var progress ActivationProgress
+	current, err := c.GetDomain(ctx, domain)
+	if err != nil {
+		progress.Err = err
+		return progress, false
+	}
+	if current.State == DomainStateActive {
+		progress.Domain = current
+		return progress, false
+	}
+	// Diagnostics only feed the report, so failing to fetch them does not hold activation back.
+	diagnostics, err := c.GetDomainDiagnostics(ctx, domain)
+	diagnosed := err == nil
+	if diagnosed {
+		progress.Checks = diagnostics.Checks()
+		progress.Failing = diagnostics.FailingChecks()
+	}
+	progress.Domain, progress.Err = c.ActivateDomain(ctx, domain)
+	return progress, diagnosed
+}
+
+func isPermanentActivationError(err error) bool {
+	var restrictionErr *RestrictionError
+	return IsNotFound(err) || IsUnauthorized(err) || IsForbidden(err) || errors.As(err, &restrictionErr)
+}
@@ -0,0 +1,105 @@
+package migadu_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	migadu "github.com/z-xavier/migadu-go"
+	"github.com/z-xavier/migadu-go/migadutest"
+)
+
+var failingSPF = migadu.DomainDiagnostics{
+	"mx":  map[string]any{"status": "ok"},
+	"spf": map[string]any{"status": "error", "message": "SPF does not include Migadu"},
+}
+
+func TestWaitForDomainActivation(t *testing.T) {
+	s := migadutest.NewUnstartedServer()
+	s.AddDomain(migadu.Domain{Name: "example.com", State: "pending"})
+	s.SetDomainDiagnostics("example.com", failingSPF)
+	client, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var progress []migadu.ActivationProgress
+	domain, err := client.WaitForDomainActivation(context.Background(), "example.com", migadu.ActivationOptions{
+		Interval: time.Millisecond,
+		OnProgress: func(p migadu.ActivationProgress) {
+			progress = append(progress, p)
+			if p.Attempt == 2 {
+				s.SetDomainDiagnostics("example.com", migadu.DomainDiagnostics{"mx": map[string]any{"status": "ok"}, "spf": map[string]any{"status": "ok"}})
+			}
+		},
+	})
+	if err != nil || domain.State != "active" {
+		t.Fatalf("WaitForDomainActivation() = %+v, %v", domain, err)
+	}
+	if len(progress) != 3 || len(progress[0].Failing) != 1 || progress[0].Failing[0].Name != "spf" || progress[0].Next != time.Millisecond || progress[1].Next != 2*time.Millisecond {
+		t.Fatalf("progress = %+v", progress)
+	}
+	if last := progress[2]; last.Domain == nil || len(last.Checks) != 2 || last.Next != 0 {
+		t.Fatalf("last progress = %+v", last)
+	}
+	// A failing check does not stop activation from being tried; the API decides.
+	activations := 0
+	for _, request := range s.Requests() {
+		if strings.HasSuffix(request.Path, "/activate") {
+			activations++
+		}
+	}
+	if activations != 3 || progress[0].Err == nil {
+		t.Fatalf("activations = %d, first error = %v", activations, progress[0].Err)
+	}
+}
+
+func TestWaitForDomainActivationTimesOut(t *testing.T) {
+	s := migadutest.NewUnstartedServer()
+	s.AddDomain(migadu.Domain{Name: "example.com", State: "pending"})
+	s.SetDomainDiagnostics("example.com", failingSPF)
+	client, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.WaitForDomainActivation(ctx, "example.com", migadu.ActivationOptions{Interval: time.Millisecond, MaxInterval: 5 * time.Millisecond})
+	var timeoutErr *migadu.ActivationTimeoutError
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForDomainActivation() error = %v", err)
+	}
+	if timeoutErr.Attempts < 2 || len(timeoutErr.Failing) != 1 || timeoutErr.Failing[0].Name != "spf" {
+		t.Fatalf("timeout error = %+v", timeoutErr)
+	}
+
+	if _, err = client.WaitForDomainActivation(context.Background(), "missing.example", migadu.ActivationOptions{}); !migadu.IsNotFound(err) {
+		t.Fatalf("missing domain error = %v", err)
+	}
+}
+
+func TestWaitForDomainActivationIgnoresWarnings(t *testing.T) {
+	s := migadutest.NewUnstartedServer()
+	s.AddDomain(migadu.Domain{Name: "active.example", State: migadu.DomainStateActive})
+	s.AddDomain(migadu.Domain{Name: "pending.example", State: "pending"})
+	warning := migadu.DomainDiagnostics{"dmarc": map[string]any{"status": "warning", "message": "no DMARC policy"}}
+	s.SetDomainDiagnostics("active.example", warning)
+	s.SetDomainDiagnostics("pending.example", warning)
+	client, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, name := range []string{"active.example", "pending.example"} {
+		var attempts int
+		domain, err := client.WaitForDomainActivation(ctx, name, migadu.ActivationOptions{
+			Interval:   time.Millisecond,
+			OnProgress: func(migadu.ActivationProgress) { attempts++ },
+		})
+		if err != nil || domain.State != migadu.DomainStateActive || attempts != 1 {
+			t.Fatalf("WaitForDomainActivation(%s) = %+v, %v after %d attempts", name, domain, err, attempts)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package migadu
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 )
 
@@ -11,10 +12,26 @@ type Alias struct {
 	Destinations     []string `json:"destinations,omitempty"`
 	DomainName       string   `json:"domain_name,omitempty"`
 	Expireable       bool     `json:"expireable,omitempty"`
-	ExpiresOn        string   `json:"expires_on,omitempty"`
+	ExpiresOn        Date     `json:"expires_on,omitzero"`
 	IsInternal       bool     `json:"is_internal,omitempty"`
 	LocalPart        string   `json:"local_part,omitempty"`
 	RemoveUponExpiry bool     `json:"remove_upon_expiry,omitempty"`
+	// Extra holds JSON fields this version of the library does not model.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON keeps unknown fields in Extra.
+func (a *Alias) UnmarshalJSON(data []byte) error {
+	type plain Alias
+	extra, err := unmarshalWithExtra(data, (*plain)(a))
+	a.Extra = extra
+	return err
+}
+
+// MarshalJSON writes Extra back next to the known fields.
+func (a Alias) MarshalJSON() ([]byte, error) {
+	type plain Alias
+	return marshalWithExtra((*plain)(&a), a.Extra)
 }
 
 // CreateAliasRequest contains fields accepted by the alias create endpoint.
@@ -28,10 +45,28 @@ type CreateAliasRequest struct {
 type UpdateAliasRequest struct {
 	Destinations     *[]string `json:"destinations,omitempty"`
 	IsInternal       *bool     `json:"is_internal,omitempty"`
-	ExpiresOn        *string   `json:"expires_on,omitempty"`
+	ExpiresOn        *Date     `json:"expires_on,omitempty"`
 	RemoveUponExpiry *bool     `json:"remove_upon_expiry,omitempty"`
 }
 
+// Validate checks the request locally. It returns a *ValidationError listing every invalid field.
+func (r CreateAliasRequest) Validate() error {
+	var v validator
+	v.localPart("local_part", r.LocalPart)
+	v.addresses("destinations", r.Destinations, true)
+	return v.err()
+}
+
+// Validate checks the fields that are set. It returns a *ValidationError listing every invalid field.
+func (r UpdateAliasRequest) Validate() error {
+	var v validator
+	if r.Destinations != nil {
+		v.addresses("destinations", *r.Destinations, true)
+	}
+	v.date("expires_on", r.ExpiresOn)
+	return v.err()
+}
+
 // ListAliases lists all aliases for a domain.
 // It returns the aliases and any error encountered.
 func (c *Client) ListAliases(ctx context.Context, domain string) ([]*Alias, error) {
@@ -47,13 +82,11 @@ func (c *Client) ListAliases(ctx context.Context, domain string) ([]*Alias, erro
 		return nil, err
 	}
 
-	resp, err := doRequest[struct {
-		Aliases []*Alias `json:"address_aliases,omitempty"`
-	}](c, ctx, req)
+	items, err := doListRequest[Alias](c, ctx, req, "address_aliases")
 	if err != nil {
 		return nil, err
 	}
-	return resp.Aliases, nil
+	return items, nil
 }
 
 // GetAlias retrieves a single alias given its local part name.
@@ -76,6 +109,9 @@ func (c *Client) GetAlias(ctx context.Context, domain, localPart string) (*Alias
 
 // CreateAlias creates an alias using all fields supported by the API.
 func (c *Client) CreateAlias(ctx context.Context, domain string, alias CreateAliasRequest) (*Alias, error) {
+	if err := c.validate(alias); err != nil {
+		return nil, err
+	}
 	builder, err := c.getDomainReqBuilder(domain)
 	if err != nil {
 		return nil, err
@@ -94,6 +130,9 @@ func (c *Client) CreateAlias(ctx context.Context, domain string, alias CreateAli
 
 // UpdateAlias updates only fields explicitly set on update.
 func (c *Client) UpdateAlias(ctx context.Context, domain, localPart string, update UpdateAliasRequest) (*Alias, error) {
+	if err := c.validate(update); err != nil {
+		return nil, err
+	}
 	builder, err := c.getDomainReqBuilder(domain)
 	if err != nil {
 		return nil, err
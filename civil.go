@@ -0,0 +1,245 @@
+package migadu
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date without a time zone, used for the yyyy-mm-dd expiry fields.
+// The zero Date means no date and is encoded as an empty string. Encoding an invalid
+// date such as 2024-02-30 fails, so a request carrying one is never sent.
+//
+// Decoding JSON is lenient instead: a value that does not parse leaves the date
+// zero and keeps the text in Raw, so one odd field does not fail a whole response.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+
+	raw string
+}
+
+// NewDate returns the date for year, month and day without normalizing it.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// DateOf returns the date of t in t's location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ParseDate parses a yyyy-mm-dd date. It also accepts timestamps and keeps their date.
+func ParseDate(value string) (Date, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Date{}, nil
+	}
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return DateOf(t), nil
+	}
+	if t, err := parseTimestamp(value); err == nil {
+		return DateOf(t), nil
+	}
+	return Date{}, fmt.Errorf("invalid date %q, want yyyy-mm-dd", value)
+}
+
+// IsZero reports whether d has no year, month and day, which includes a Date holding unparsed Raw text.
+func (d Date) IsZero() bool {
+	return d.Year == 0 && d.Month == 0 && d.Day == 0
+}
+
+// Raw returns the text of a value that UnmarshalJSON could not parse, or "".
+func (d Date) Raw() string {
+	return d.raw
+}
+
+// IsValid reports whether d is an actual calendar date between years 1 and 9999.
+func (d Date) IsValid() bool {
+	return d.Year >= 1 && d.Year <= 9999 && DateOf(d.In(time.UTC)) == d
+}
+
+// In returns the start of d in loc.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// String returns d as yyyy-mm-dd, or its Raw text for the zero Date.
+func (d Date) String() string {
+	if d.IsZero() {
+		return d.raw
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, int(d.Month), d.Day)
+}
+
+// MarshalText encodes d as yyyy-mm-dd and rejects invalid dates and unparsed Raw text.
+func (d Date) MarshalText() ([]byte, error) {
+	if d.raw != "" {
+		return nil, fmt.Errorf("invalid date %q", d.raw)
+	}
+	if !d.IsZero() && !d.IsValid() {
+		return nil, fmt.Errorf("invalid date %04d-%02d-%02d", d.Year, int(d.Month), d.Day)
+	}
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses a date as ParseDate does.
+func (d *Date) UnmarshalText(data []byte) error {
+	date, err := ParseDate(string(data))
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
+
+// MarshalJSON encodes d as a yyyy-mm-dd string and rejects invalid dates.
+func (d Date) MarshalJSON() ([]byte, error) {
+	text, err := d.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON accepts null and empty strings as the zero Date, besides the forms
+// ParseDate accepts. Any other string decodes to the zero Date with the text kept in Raw.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Date{}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if err := d.UnmarshalText([]byte(value)); err != nil {
+		*d = Date{raw: value}
+	}
+	return nil
+}
+
+// Timestamp is an instant returned by the API, such as a mailbox's last login.
+// The zero Timestamp means no time and is encoded as an empty string. Like Date,
+// it decodes a JSON string that does not parse to the zero time and keeps the text in Raw.
+type Timestamp struct {
+	time.Time
+
+	raw string
+}
+
+// NewTimestamp returns t as a Timestamp.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// timestampLayouts are tried in order. Layouts without a zone are read as UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	time.RFC1123Z,
+	time.RFC1123,
+	dateLayout,
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q, want RFC 3339", value)
+}
+
+// ParseTimestamp parses an RFC 3339 timestamp. It also accepts a space instead of
+// the "T", a missing zone, which is read as UTC, RFC 1123 and plain dates.
+func ParseTimestamp(value string) (Timestamp, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Timestamp{}, nil
+	}
+	t, err := parseTimestamp(value)
+	return Timestamp{Time: t}, err
+}
+
+// Raw returns the text of a value that UnmarshalJSON could not parse, or "".
+func (t Timestamp) Raw() string {
+	return t.raw
+}
+
+// String returns t in RFC 3339 format, or its Raw text for the zero Timestamp.
+func (t Timestamp) String() string {
+	if t.IsZero() {
+		return t.raw
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// MarshalText encodes t in RFC 3339 format and rejects unparsed Raw text.
+func (t Timestamp) MarshalText() ([]byte, error) {
+	if t.raw != "" {
+		return nil, fmt.Errorf("invalid timestamp %q", t.raw)
+	}
+	if t.IsZero() {
+		return []byte{}, nil
+	}
+	return t.Time.MarshalText()
+}
+
+// UnmarshalText parses a timestamp as ParseTimestamp does.
+func (t *Timestamp) UnmarshalText(data []byte) error {
+	timestamp, err := ParseTimestamp(string(data))
+	if err != nil {
+		return err
+	}
+	*t = timestamp
+	return nil
+}
+
+// MarshalJSON encodes t as an RFC 3339 string.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	text, err := t.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON accepts null and empty strings as the zero Timestamp, and numbers as
+// Unix seconds, besides the forms ParseTimestamp accepts. Any other string decodes
+// to the zero Timestamp with the text kept in Raw.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = Timestamp{}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		if err := t.UnmarshalText([]byte(value)); err != nil {
+			*t = Timestamp{raw: value}
+		}
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+	seconds, err := strconv.ParseFloat(number.String(), 64)
+	if err != nil {
+		return err
+	}
+	whole := int64(seconds)
+	*t = Timestamp{Time: time.Unix(whole, int64((seconds-float64(whole))*1e9)).UTC()}
+	return nil
+}
@@ -0,0 +1,113 @@
+package migadu
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDateUnmarshalIsLenient(t *testing.T) {
+	tests := map[string]Date{
+		`"2024-05-01"`:           NewDate(2024, time.May, 1),
+		`"2024-05-01T23:30:00Z"`: NewDate(2024, time.May, 1),
+		`" 2024-05-01 "`:         NewDate(2024, time.May, 1),
+		`""`:                     {},
+		`null`:                   {},
+	}
+	for input, want := range tests {
+		var got Date
+		if err := json.Unmarshal([]byte(input), &got); err != nil || got != want {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"01/05/2024", "2024-02-30"} {
+		var date Date
+		if err := json.Unmarshal([]byte(`"`+input+`"`), &date); err != nil || !date.IsZero() || date.Raw() != input {
+			t.Errorf("Unmarshal(%q) = %#v, %v, want zero date with raw text", input, date, err)
+		}
+		if _, err := json.Marshal(date); err == nil {
+			t.Errorf("Marshal() of unparsed %q succeeded", input)
+		}
+	}
+	var date Date
+	if err := json.Unmarshal([]byte(`20240501`), &date); err == nil {
+		t.Errorf("Unmarshal(20240501) = %v, want error", date)
+	}
+}
+
+func TestMalformedDateDoesNotFailList(t *testing.T) {
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{"mailboxes":[{"local_part":"old","expires_on":"31/12/2025","last_login_at":"yesterday"},{"local_part":"new","expires_on":"2025-12-31"}]}`
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mailboxes, err := client.ListMailboxes(context.Background(), "example.com")
+	if err != nil || len(mailboxes) != 2 {
+		t.Fatalf("ListMailboxes() = %+v, %v", mailboxes, err)
+	}
+	old := mailboxes[0]
+	if !old.ExpiresOn.IsZero() || old.ExpiresOn.Raw() != "31/12/2025" || !old.LastLoginAt.IsZero() || old.LastLoginAt.Raw() != "yesterday" {
+		t.Fatalf("malformed mailbox = %+v", old)
+	}
+	if mailboxes[1].ExpiresOn != NewDate(2025, time.December, 31) {
+		t.Fatalf("valid mailbox = %+v", mailboxes[1])
+	}
+}
+
+func TestTimestampUnmarshalIsLenient(t *testing.T) {
+	want := time.Date(2024, time.May, 1, 12, 30, 15, 0, time.UTC)
+	for _, input := range []string{`"2024-05-01T12:30:15Z"`, `"2024-05-01T14:30:15+02:00"`, `"2024-05-01 12:30:15"`, `"2024-05-01T12:30:15"`, `1714566615`} {
+		var got Timestamp
+		if err := json.Unmarshal([]byte(input), &got); err != nil || !got.Equal(want) {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	var mailbox Mailbox
+	data := `{"local_part":"demo","changed_at":"2024-05-01T12:30:15Z","last_login_at":null,"expires_on":"","autorespond_expires_on":"2024-06-01"}`
+	if err := json.Unmarshal([]byte(data), &mailbox); err != nil {
+		t.Fatal(err)
+	}
+	if !mailbox.ChangedAt.Equal(want) || !mailbox.LastLoginAt.IsZero() || !mailbox.ExpiresOn.IsZero() || mailbox.AutorespondExpiresOn != NewDate(2024, time.June, 1) {
+		t.Fatalf("mailbox = %+v", mailbox)
+	}
+	encoded, err := json.Marshal(mailbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"autorespond_expires_on":"2024-06-01","changed_at":"2024-05-01T12:30:15Z","local_part":"demo"}`; string(encoded) != want {
+		t.Fatalf("Marshal() = %s, want %s", encoded, want)
+	}
+}
+
+func TestInvalidDateIsNotSent(t *testing.T) {
+	calls := 0
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return nil, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiresOn := NewDate(2024, time.February, 30)
+	request := UpdateAliasRequest{ExpiresOn: &expiresOn}
+	if _, err = client.UpdateAlias(context.Background(), "example.com", "info", request); !IsValidation(err) || calls != 0 {
+		t.Fatalf("UpdateAlias() error = %v after %d calls", err, calls)
+	}
+	// Encoding rejects the date even when validation is skipped.
+	_, err = client.With(WithoutValidation()).UpdateAlias(context.Background(), "example.com", "info", request)
+	if err == nil || !strings.Contains(err.Error(), "invalid date 2024-02-30") || calls != 0 {
+		t.Fatalf("UpdateAlias() without validation error = %v after %d calls", err, calls)
+	}
+}
@@ -1,12 +1,14 @@
 package migadu
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"reflect"
 	"strings"
 	"time"
 )
@@ -15,6 +17,8 @@ const (
 	defaultAPIHost = "https://api.migadu.com"
 	v1Path         = "v1"
 	DefaultTimeout = 30 * time.Second
+	// DefaultUserAgent identifies this library when no user agent is configured.
+	DefaultUserAgent = "migadu-go"
 
 	domainsPath     = "domains"
 	aliasesPath     = "aliases"
@@ -36,23 +40,70 @@ type HTTPDoer interface {
 }
 
 // Client represents a client for working with Migadu API.
+// It is configured only through Option values passed to NewWithOptions or With,
+// so it cannot change once built and is safe for concurrent use.
 type Client struct {
-	BaseURL    string
-	Timeout    time.Duration
-	HTTPClient HTTPDoer
-	email      string
-	apiKey     string
+	baseURL    string
+	timeout    time.Duration
+	httpClient HTTPDoer
+	userAgent  string
+	header     http.Header
+	retry      *RetryPolicy
+	limiter    *RateLimiter
+	middleware []Middleware
+	logger     *slog.Logger
+	dryRun     *Plan
+	// maxResponseBytes limits the size of a response body. Zero means no limit.
+	maxResponseBytes int64
+	// schemaDriftHandler, when set, receives differences between responses and the model.
+	schemaDriftHandler func(SchemaDrift)
+	// skipValidation sends Create and Update requests without validating them locally first.
+	skipValidation bool
+	credentials    CredentialsProvider
+	// readOnly and allowedDomains are set by ReadOnly and RestrictToDomains and cannot be undone.
+	readOnly       bool
+	allowedDomains map[string]struct{}
+}
+
+// BaseURL returns the API base URL set with WithBaseURL.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Timeout returns the per-call timeout set with WithTimeout.
+func (c *Client) Timeout() time.Duration {
+	return c.timeout
+}
+
+// HTTPClient returns the HTTPDoer set with WithHTTPClient, without the middlewares around it.
+func (c *Client) HTTPClient() HTTPDoer {
+	return c.httpClient
 }
 
 func (c *Client) getV1ReqBuilder() *httpReqBuilder {
-	baseURL := c.BaseURL
+	baseURL := c.baseURL
 	if baseURL == "" {
 		baseURL = defaultAPIHost
 	}
 	builder := newReqBuilder().
 		SetHost(baseURL).
-		AddPath(v1Path).
-		SetBasicAuth(c.email, c.apiKey)
+		AddPath(v1Path)
+	if c.credentials != nil {
+		credentials, err := c.credentials.Credentials()
+		if err != nil {
+			builder.err = err
+		} else {
+			builder.SetBasicAuth(credentials.Email, credentials.APIKey)
+		}
+	}
+	for key, values := range c.header {
+		for _, value := range values {
+			builder.AddHeader(key, value)
+		}
+	}
+	if c.userAgent != "" {
+		builder.SetHeader("User-Agent", c.userAgent)
+	}
 	return builder
 }
 
@@ -60,36 +111,32 @@ func (c *Client) getDomainReqBuilder(domain string) (*httpReqBuilder, error) {
 	if strings.TrimSpace(domain) == "" {
 		return nil, ErrDomainRequired
 	}
+	if err := c.checkDomain(domain); err != nil {
+		return nil, err
+	}
 	return c.getV1ReqBuilder().AddRestfulPath(domainsPath, domain), nil
 }
 
 // New creates a Migadu API client without making a network request.
 func New(email, apiKey string) (*Client, error) {
-	if strings.TrimSpace(email) == "" {
-		return nil, ErrEmailRequired
-	}
-	if strings.TrimSpace(apiKey) == "" {
-		return nil, ErrAPIKeyRequired
-	}
-	return &Client{
-		BaseURL:    defaultAPIHost,
-		Timeout:    DefaultTimeout,
-		HTTPClient: http.DefaultClient,
-		email:      email,
-		apiKey:     apiKey,
-	}, nil
+	return NewWithOptions(email, apiKey)
 }
 
 // APIError describes a non-success response returned by the Migadu API.
+// FieldErrors lists per-field problems when the response body carries them.
 type APIError struct {
-	StatusCode int
-	Code       string `json:"error"`
-	Message    string `json:"message"`
-	Body       string `json:"-"`
+	StatusCode  int
+	Code        string       `json:"error"`
+	Message     string       `json:"message"`
+	FieldErrors []FieldError `json:"-"`
+	Body        string       `json:"-"`
 }
 
 func (e *APIError) Error() string {
 	detail := e.Message
+	if detail == "" && len(e.FieldErrors) > 0 {
+		detail = formatFieldErrors(e.FieldErrors)
+	}
 	if detail == "" {
 		detail = e.Code
 	}
@@ -103,38 +150,177 @@ func (e *APIError) Error() string {
 }
 
 func doRequest[T any](c *Client, ctx context.Context, req *http.Request) (*T, error) {
-	if c.Timeout != 0 {
+	var result T
+	err := c.call(ctx, req, reflect.TypeFor[T](), func(body *responseReader) error {
+		// A retried attempt decodes again from scratch.
+		result = *new(T)
+		return decodeJSON(body, &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// doListRequest decodes the array under key of a list response one element at a time.
+func doListRequest[T any](c *Client, ctx context.Context, req *http.Request, key string) ([]*T, error) {
+	var items []*T
+	err := c.call(ctx, req, listSchema(key, reflect.TypeFor[T]()), func(body *responseReader) error {
+		var err error
+		items, err = decodeListJSON[T](body, key)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// call sends req, or records it in dry-run mode, and streams the response body into decode.
+// Responses that are received are compared with schema when schema drift is reported.
+func (c *Client) call(ctx context.Context, req *http.Request, schema reflect.Type, decode func(*responseReader) error) error {
+	if c.timeout != 0 {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
 		defer cancel()
 	}
-	req = req.WithContext(ctx)
-	httpClient := c.HTTPClient
-	if httpClient == nil {
-		httpClient = http.DefaultClient
+	if err := c.checkReadOnly(req); err != nil {
+		return err
 	}
-	resp, err := httpClient.Do(req)
+	if c.dryRun != nil && isMutation(req) {
+		body, err := c.plan(ctx, req)
+		if err != nil {
+			return err
+		}
+		return decode(newResponseReader(bytes.NewReader(body), 0))
+	}
+	return c.sendWithRetry(ctx, req, c.checkDrift(req, schema, decode))
+}
+
+// sendWithRetry executes req, retrying according to c.retry. The client timeout covers all attempts.
+// A 401 refreshes the credentials once and resends without counting as a retry.
+// The successful response body is streamed into decode.
+func (c *Client) sendWithRetry(ctx context.Context, req *http.Request, decode func(*responseReader) error) error {
+	attempts := c.retry.maxAttempts(req.Method)
+	replayable := req.Body == nil || req.GetBody != nil
+	if !replayable {
+		attempts = 1
+	}
+	info := responseInfoFrom(ctx)
+	if info != nil {
+		*info = ResponseInfo{}
+	}
+	refreshed := false
+	for attempt, sent := 1, false; ; sent = true {
+		attemptReq := req.WithContext(ctx)
+		if sent && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			attemptReq.Body = body
+		}
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		if info != nil {
+			info.Attempts++
+		}
+		retryAfter, err := c.send(attemptReq, decode)
+		if err == nil {
+			return nil
+		}
+		if !refreshed && replayable && IsUnauthorized(err) {
+			refreshed = true
+			if refreshedReq, ok := c.refreshCredentials(req); ok {
+				req = refreshedReq
+				continue
+			}
+		}
+		if attempt >= attempts || !isRetryableError(ctx, err) {
+			return err
+		}
+		delay := c.retry.backoff(attempt)
+		if retryAfter >= 0 {
+			delay = retryAfter
+		}
+		if !waitRetry(ctx, delay) {
+			return err
+		}
+		attempt++
+	}
+}
+
+// refreshCredentials reloads credentials from a refreshable provider and returns req with the new Basic Auth.
+func (c *Client) refreshCredentials(req *http.Request) (*http.Request, bool) {
+	refresher, ok := c.credentials.(CredentialsRefresher)
+	if !ok {
+		return nil, false
+	}
+	if err := refresher.Refresh(); err != nil {
+		return nil, false
+	}
+	credentials, err := c.credentials.Credentials()
 	if err != nil {
-		return nil, err
+		return nil, false
+	}
+	refreshedReq := req.Clone(req.Context())
+	refreshedReq.SetBasicAuth(credentials.Email, credentials.APIKey)
+	return refreshedReq, true
+}
+
+// doer assembles the transport for one attempt: the user middlewares wrap the
+// logger, which sits closest to the HTTP client so it logs the request as sent.
+func (c *Client) doer() HTTPDoer {
+	var doer HTTPDoer = c.httpClient
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+	if c.logger != nil {
+		doer = loggingMiddleware(c.logger)(doer)
+	}
+	return Chain(doer, c.middleware...)
+}
+
+// send performs a single attempt, decoding a successful body as it is read.
+// It returns the Retry-After delay, or -1 when the header is absent.
+func (c *Client) send(req *http.Request, decode func(*responseReader) error) (time.Duration, error) {
+	started := time.Now()
+	resp, err := c.doer().Do(req)
+	if err != nil {
+		if info := responseInfoFrom(req.Context()); info != nil {
+			info.captureFailure(started)
+		}
+		return -1, err
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	retryAfter := time.Duration(-1)
+	if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		retryAfter = delay
+	}
+	var raw io.Reader = resp.Body
+	if info := responseInfoFrom(req.Context()); info != nil {
+		var done func()
+		raw, done = info.captureBody(resp, started)
+		defer done()
 	}
+	body := newResponseReader(raw, c.maxResponseBytes)
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		apiErr := &APIError{StatusCode: resp.StatusCode, Body: string(body)}
-		_ = json.Unmarshal(body, apiErr)
-		return nil, apiErr
+		// Error bodies are small and kept whole on the APIError.
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return retryAfter, err
+		}
+		return retryAfter, parseAPIError(resp.StatusCode, data)
 	}
-	var result T
-	if len(strings.TrimSpace(string(body))) == 0 {
-		return &result, nil
+	if err = decode(body); err != nil {
+		return retryAfter, err
 	}
-	if err = json.Unmarshal(body, &result); err != nil {
-		return nil, err
+	if raw != resp.Body {
+		// Read past the decoded value so the captured body is complete.
+		_, _ = io.Copy(io.Discard, body)
 	}
-	return &result, nil
+	return retryAfter, nil
 }
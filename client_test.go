@@ -26,8 +26,8 @@ func performRequest(t *testing.T, response string, call func(context.Context, *C
 	if err != nil {
 		t.Fatalf("New() error = %v", err)
 	}
-	client.BaseURL = "https://api.test"
-	client.HTTPClient = doerFunc(func(r *http.Request) (*http.Response, error) {
+	client.baseURL = "https://api.test"
+	client.httpClient = doerFunc(func(r *http.Request) (*http.Response, error) {
 		var body []byte
 		if r.Body != nil {
 			var err error
@@ -58,7 +58,7 @@ func TestNewDoesNotMakeRequest(t *testing.T) {
 	if err != nil {
 		t.Fatalf("New() error = %v", err)
 	}
-	if client.BaseURL != defaultAPIHost {
+	if client.baseURL != defaultAPIHost {
 		t.Fatalf("New() client = %+v", client)
 	}
 }
@@ -110,7 +110,7 @@ func (b *trackedBody) Close() error {
 
 func TestRequestReturnsAPIErrorAndClosesBody(t *testing.T) {
 	body := &trackedBody{Reader: strings.NewReader(`{"error":"dns_check_failed","message":"DNS checks failed"}`)}
-	client := &Client{HTTPClient: doerFunc(func(*http.Request) (*http.Response, error) {
+	client := &Client{httpClient: doerFunc(func(*http.Request) (*http.Response, error) {
 		return &http.Response{StatusCode: http.StatusUnprocessableEntity, ContentLength: -1, Body: body}, nil
 	})}
 	req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
@@ -131,7 +131,7 @@ func TestRequestReturnsAPIErrorAndClosesBody(t *testing.T) {
 }
 
 func TestRequestAcceptsEmptySuccessBody(t *testing.T) {
-	client := &Client{HTTPClient: doerFunc(func(*http.Request) (*http.Response, error) {
+	client := &Client{httpClient: doerFunc(func(*http.Request) (*http.Response, error) {
 		return &http.Response{StatusCode: http.StatusNoContent, Body: io.NopCloser(strings.NewReader(""))}, nil
 	})}
 	req, _ := http.NewRequest(http.MethodDelete, "https://example.com", nil)
@@ -143,8 +143,8 @@ func TestRequestAcceptsEmptySuccessBody(t *testing.T) {
 
 func TestRequestAppliesTimeout(t *testing.T) {
 	client := &Client{
-		Timeout: time.Millisecond,
-		HTTPClient: doerFunc(func(req *http.Request) (*http.Response, error) {
+		timeout: time.Millisecond,
+		httpClient: doerFunc(func(req *http.Request) (*http.Response, error) {
 			<-req.Context().Done()
 			return nil, req.Context().Err()
 		}),
@@ -160,7 +160,7 @@ func TestRequestUsesContextWhenTimeoutDisabled(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 	client := &Client{
-		HTTPClient: doerFunc(func(req *http.Request) (*http.Response, error) {
+		httpClient: doerFunc(func(req *http.Request) (*http.Response, error) {
 			return nil, req.Context().Err()
 		}),
 	}
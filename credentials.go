@@ -0,0 +1,191 @@
+package migadu
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EnvAdminEmail = "MIGADU_ADMIN_EMAIL"
+	EnvAPIKey     = "MIGADU_API_KEY"
+)
+
+var ErrCredentialsRequired = errors.New("credentials provider is required")
+
+// Credentials holds the admin email and API key used for Basic Auth.
+type Credentials struct {
+	Email  string `json:"email"`
+	APIKey string `json:"api_key"`
+}
+
+func (c Credentials) validate() error {
+	if strings.TrimSpace(c.Email) == "" {
+		return ErrEmailRequired
+	}
+	if strings.TrimSpace(c.APIKey) == "" {
+		return ErrAPIKeyRequired
+	}
+	return nil
+}
+
+// CredentialsProvider supplies credentials for every request, which lets long-running
+// services rotate API keys without restarting.
+type CredentialsProvider interface {
+	Credentials() (Credentials, error)
+}
+
+// CredentialsRefresher is implemented by providers that can reload credentials on demand.
+// The client calls Refresh once when Migadu answers 401 and then retries the request.
+type CredentialsRefresher interface {
+	Refresh() error
+}
+
+type staticCredentials Credentials
+
+// StaticCredentials returns a provider that always returns the same credentials.
+func StaticCredentials(email, apiKey string) CredentialsProvider {
+	return staticCredentials{Email: email, APIKey: apiKey}
+}
+
+func (s staticCredentials) Credentials() (Credentials, error) {
+	return Credentials(s), nil
+}
+
+type envCredentials struct{}
+
+// EnvCredentials returns a provider reading MIGADU_ADMIN_EMAIL and MIGADU_API_KEY on every request.
+func EnvCredentials() CredentialsProvider {
+	return envCredentials{}
+}
+
+func (envCredentials) Credentials() (Credentials, error) {
+	credentials := Credentials{Email: os.Getenv(EnvAdminEmail), APIKey: os.Getenv(EnvAPIKey)}
+	if err := credentials.validate(); err != nil {
+		return Credentials{}, fmt.Errorf("read credentials from environment: %w", err)
+	}
+	return credentials, nil
+}
+
+func (envCredentials) Refresh() error {
+	return nil
+}
+
+// FileCredentials reads credentials from a file, such as a mounted secret, and
+// re-reads it whenever its size or modification time changes. The file holds
+// either a JSON object {"email": "...", "api_key": "..."} or
+// MIGADU_ADMIN_EMAIL=... and MIGADU_API_KEY=... lines.
+type FileCredentials struct {
+	path string
+
+	mu          sync.Mutex
+	modTime     time.Time
+	size        int64
+	credentials Credentials
+	loaded      bool
+}
+
+// NewFileCredentials creates a provider for the credentials file at path.
+// The file is read lazily on the first request.
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{path: path}
+}
+
+// Credentials returns the current credentials, re-reading the file if it changed.
+func (f *FileCredentials) Credentials() (Credentials, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("read credentials file: %w", err)
+	}
+	if f.loaded && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.credentials, nil
+	}
+	return f.load(info)
+}
+
+// Refresh re-reads the file even if it looks unchanged.
+func (f *FileCredentials) Refresh() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("read credentials file: %w", err)
+	}
+	_, err = f.load(info)
+	return err
+}
+
+func (f *FileCredentials) load(info os.FileInfo) (Credentials, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("read credentials file: %w", err)
+	}
+	credentials, err := parseCredentialsFile(data)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("parse credentials file %s: %w", f.path, err)
+	}
+	f.credentials = credentials
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	f.loaded = true
+	return credentials, nil
+}
+
+func parseCredentialsFile(data []byte) (Credentials, error) {
+	var credentials Credentials
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		if err := json.Unmarshal(trimmed, &credentials); err != nil {
+			return Credentials{}, err
+		}
+		return credentials, credentials.validate()
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.TrimSpace(key) {
+		case EnvAdminEmail:
+			credentials.Email = value
+		case EnvAPIKey:
+			credentials.APIKey = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Credentials{}, err
+	}
+	return credentials, credentials.validate()
+}
+
+// WithCredentialsProvider replaces the credentials used by the client.
+func WithCredentialsProvider(provider CredentialsProvider) Option {
+	return func(c *Client) {
+		c.credentials = provider
+	}
+}
+
+// NewWithCredentials creates a client that asks provider for credentials on every request.
+func NewWithCredentials(provider CredentialsProvider, options ...Option) (*Client, error) {
+	if provider == nil {
+		return nil, ErrCredentialsRequired
+	}
+	c := newClient(provider)
+	for _, option := range options {
+		option(c)
+	}
+	return c, nil
+}
@@ -0,0 +1,104 @@
+package migadu
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv(EnvAdminEmail, "admin@example.com")
+	t.Setenv(EnvAPIKey, "")
+	if _, err := EnvCredentials().Credentials(); !errors.Is(err, ErrAPIKeyRequired) {
+		t.Fatalf("Credentials() error = %v, want %v", err, ErrAPIKeyRequired)
+	}
+	t.Setenv(EnvAPIKey, "secret")
+	credentials, err := EnvCredentials().Credentials()
+	if err != nil || credentials != (Credentials{Email: "admin@example.com", APIKey: "secret"}) {
+		t.Fatalf("Credentials() = %+v, %v", credentials, err)
+	}
+}
+
+func TestFileCredentialsReloadsChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "migadu")
+	if err := os.WriteFile(path, []byte("# mounted secret\nMIGADU_ADMIN_EMAIL=admin@example.com\nexport MIGADU_API_KEY=\"old\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider := NewFileCredentials(path)
+	credentials, err := provider.Credentials()
+	if err != nil || credentials.APIKey != "old" || credentials.Email != "admin@example.com" {
+		t.Fatalf("Credentials() = %+v, %v", credentials, err)
+	}
+	if err = os.WriteFile(path, []byte(`{"email":"admin@example.com","api_key":"rotated"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err = os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	credentials, err = provider.Credentials()
+	if err != nil || credentials.APIKey != "rotated" {
+		t.Fatalf("Credentials() after rotation = %+v, %v", credentials, err)
+	}
+}
+
+type rotatingCredentials struct {
+	keys      []string
+	refreshes int
+}
+
+func (r *rotatingCredentials) Credentials() (Credentials, error) {
+	return Credentials{Email: "admin@example.com", APIKey: r.keys[r.refreshes]}, nil
+}
+
+func (r *rotatingCredentials) Refresh() error {
+	r.refreshes++
+	return nil
+}
+
+func TestUnauthorizedRefreshesCredentialsOnce(t *testing.T) {
+	provider := &rotatingCredentials{keys: []string{"stale", "fresh", "unused"}}
+	var keys, bodies []string
+	client, err := NewWithCredentials(provider,
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			_, key, _ := req.BasicAuth()
+			var body []byte
+			if req.Body != nil {
+				body, _ = io.ReadAll(req.Body)
+			}
+			keys = append(keys, key)
+			bodies = append(bodies, string(body))
+			status := http.StatusUnauthorized
+			if key == "fresh" && len(keys) == 2 {
+				status = http.StatusOK
+			}
+			return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.CreateAlias(context.Background(), "example.com", CreateAliasRequest{LocalPart: "info", Destinations: []string{"demo@example.com"}}); err != nil {
+		t.Fatalf("CreateAlias() error = %v", err)
+	}
+	if strings.Join(keys, ",") != "stale,fresh" || bodies[0] != bodies[1] || bodies[1] == "" {
+		t.Fatalf("keys = %q, bodies = %q", keys, bodies)
+	}
+	_, err = client.GetAlias(context.Background(), "example.com", "info")
+	if !IsUnauthorized(err) || provider.refreshes != 2 || len(keys) != 4 {
+		t.Fatalf("error = %v, refreshes = %d, keys = %q", err, provider.refreshes, keys)
+	}
+}
+
+func TestNewWithCredentialsRequiresProvider(t *testing.T) {
+	if _, err := NewWithCredentials(nil); !errors.Is(err, ErrCredentialsRequired) {
+		t.Fatalf("error = %v", err)
+	}
+}
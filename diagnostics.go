@@ -0,0 +1,163 @@
+package migadu
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DiagnosticStatus is the outcome of one DNS check.
+type DiagnosticStatus string
+
+const (
+	DiagnosticPass    DiagnosticStatus = "pass"
+	DiagnosticFail    DiagnosticStatus = "fail"
+	DiagnosticWarning DiagnosticStatus = "warning"
+	// DiagnosticUnknown is used when the response has no status this library recognizes.
+	DiagnosticUnknown DiagnosticStatus = "unknown"
+)
+
+// DiagnosticCheck is one check of a diagnostics response, such as MX or SPF.
+type DiagnosticCheck struct {
+	// Name is the key of the check in the response, such as "mx", "spf", "dkim" or "dmarc".
+	Name     string
+	Status   DiagnosticStatus
+	Expected []string
+	Actual   []string
+	Messages []string
+}
+
+func (c DiagnosticCheck) String() string {
+	if len(c.Messages) == 0 {
+		return fmt.Sprintf("%s: %s", c.Name, c.Status)
+	}
+	return fmt.Sprintf("%s: %s: %s", c.Name, c.Status, strings.Join(c.Messages, "; "))
+}
+
+// checkOrder lists the well-known checks first; other checks follow by name.
+var checkOrder = map[string]int{"mx": 1, "spf": 2, "dkim": 3, "dmarc": 4}
+
+// Checks interprets the diagnostics as typed checks. Every top-level object with a
+// recognizable status becomes a check; everything else stays available only in the map.
+// Several spellings are accepted because the schema is not documented: the status may be
+// a string such as "ok" or "error" or a boolean, and values may be strings or lists.
+func (d DomainDiagnostics) Checks() []DiagnosticCheck {
+	var checks []DiagnosticCheck
+	for name, value := range d {
+		fields, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		status, ok := diagnosticStatus(fields)
+		if !ok {
+			continue
+		}
+		checks = append(checks, DiagnosticCheck{
+			Name:     name,
+			Status:   status,
+			Expected: diagnosticValues(fields, "expected", "expected_value", "expected_values", "expected_records"),
+			Actual:   diagnosticValues(fields, "actual", "found", "current", "value", "values", "records"),
+			Messages: diagnosticValues(fields, "messages", "message", "errors", "error", "warnings", "details"),
+		})
+	}
+	sort.Slice(checks, func(i, j int) bool {
+		oi, oj := checkOrder[strings.ToLower(checks[i].Name)], checkOrder[strings.ToLower(checks[j].Name)]
+		if oi != oj {
+			return oi != 0 && (oj == 0 || oi < oj)
+		}
+		return checks[i].Name < checks[j].Name
+	})
+	return checks
+}
+
+// Check returns the check with the given name, matched case-insensitively.
+func (d DomainDiagnostics) Check(name string) (DiagnosticCheck, bool) {
+	for _, check := range d.Checks() {
+		if strings.EqualFold(check.Name, name) {
+			return check, true
+		}
+	}
+	return DiagnosticCheck{}, false
+}
+
+// FailingChecks returns every check whose status is not DiagnosticPass, including warnings and unknown statuses.
+func (d DomainDiagnostics) FailingChecks() []DiagnosticCheck {
+	var failing []DiagnosticCheck
+	for _, check := range d.Checks() {
+		if check.Status != DiagnosticPass {
+			failing = append(failing, check)
+		}
+	}
+	return failing
+}
+
+// AllPassing reports whether the diagnostics contain at least one check and every check passed.
+func (d DomainDiagnostics) AllPassing() bool {
+	checks := d.Checks()
+	for _, check := range checks {
+		if check.Status != DiagnosticPass {
+			return false
+		}
+	}
+	return len(checks) > 0
+}
+
+func diagnosticStatus(fields map[string]any) (DiagnosticStatus, bool) {
+	for _, key := range []string{"status", "state", "result", "valid", "ok", "passed", "success"} {
+		switch value := fields[key].(type) {
+		case bool:
+			if value {
+				return DiagnosticPass, true
+			}
+			return DiagnosticFail, true
+		case string:
+			return parseDiagnosticStatus(value), true
+		}
+	}
+	return "", false
+}
+
+func parseDiagnosticStatus(value string) DiagnosticStatus {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "ok", "pass", "passed", "passing", "valid", "success", "succeeded", "green", "correct", "true":
+		return DiagnosticPass
+	case "error", "fail", "failed", "failing", "invalid", "missing", "incorrect", "mismatch", "red", "false":
+		return DiagnosticFail
+	case "warning", "warn", "yellow", "partial":
+		return DiagnosticWarning
+	}
+	return DiagnosticUnknown
+}
+
+// diagnosticValues returns the first of keys present in fields, flattened to strings.
+func diagnosticValues(fields map[string]any, keys ...string) []string {
+	for _, key := range keys {
+		if value, ok := fields[key]; ok && value != nil {
+			return flattenDiagnosticValue(value)
+		}
+	}
+	return nil
+}
+
+func flattenDiagnosticValue(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []any:
+		var values []string
+		for _, item := range v {
+			values = append(values, flattenDiagnosticValue(item)...)
+		}
+		return values
+	case map[string]any:
+		data, _ := json.Marshal(v)
+		return []string{string(data)}
+	}
+	return []string{fmt.Sprint(value)}
+}
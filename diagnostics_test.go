@@ -0,0 +1,59 @@
+package migadu
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDomainDiagnosticsChecks(t *testing.T) {
+	var diagnostics DomainDiagnostics
+	data := `{
+		"domain": "example.com",
+		"spf": {"status": "error", "expected": "v=spf1 include:spf.migadu.com -all", "found": ["v=spf1 -all"], "message": "SPF does not include Migadu"},
+		"mx": {"status": "ok", "expected": ["aspmx1.migadu.com", "aspmx2.migadu.com"], "found": ["aspmx1.migadu.com", "aspmx2.migadu.com"]},
+		"dmarc": {"valid": true},
+		"autoconfig": {"state": "warning", "errors": ["CNAME missing"]},
+		"dkim": {"status": "ok"},
+		"notes": ["checked recently"]
+	}`
+	if err := json.Unmarshal([]byte(data), &diagnostics); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, check := range diagnostics.Checks() {
+		names = append(names, check.Name)
+	}
+	if want := []string{"mx", "spf", "dkim", "dmarc", "autoconfig"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("checks = %v, want %v", names, want)
+	}
+	spf, ok := diagnostics.Check("SPF")
+	want := DiagnosticCheck{
+		Name:     "spf",
+		Status:   DiagnosticFail,
+		Expected: []string{"v=spf1 include:spf.migadu.com -all"},
+		Actual:   []string{"v=spf1 -all"},
+		Messages: []string{"SPF does not include Migadu"},
+	}
+	if !ok || !reflect.DeepEqual(spf, want) {
+		t.Fatalf("Check(spf) = %+v", spf)
+	}
+	failing := diagnostics.FailingChecks()
+	if len(failing) != 2 || failing[0].Name != "spf" || failing[1].Status != DiagnosticWarning {
+		t.Fatalf("FailingChecks() = %+v", failing)
+	}
+	if diagnostics.AllPassing() {
+		t.Fatal("AllPassing() = true")
+	}
+	if diagnostics["domain"] != "example.com" {
+		t.Fatal("raw map lost unknown keys")
+	}
+
+	passing := DomainDiagnostics{"mx": map[string]any{"status": "ok"}, "spf": map[string]any{"status": "pass"}}
+	if !passing.AllPassing() || len(passing.FailingChecks()) != 0 {
+		t.Fatal("passing diagnostics are not reported as passing")
+	}
+	if (DomainDiagnostics{}).AllPassing() {
+		t.Fatal("empty diagnostics are reported as passing")
+	}
+}
@@ -0,0 +1,310 @@
+package migadu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Resolver looks up the DNS records checked by DNSVerifier. *net.Resolver implements it.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupCNAME(ctx context.Context, host string) (string, error)
+}
+
+// DNSRecordStatus is the outcome of verifying one record.
+type DNSRecordStatus string
+
+const (
+	DNSRecordOK       DNSRecordStatus = "ok"
+	DNSRecordMissing  DNSRecordStatus = "missing"
+	DNSRecordMismatch DNSRecordStatus = "mismatch"
+	// DNSRecordError means the lookup itself failed, for example because of a timeout.
+	DNSRecordError DNSRecordStatus = "error"
+)
+
+// DNSRecordResult is the outcome of verifying one required record.
+type DNSRecordResult struct {
+	Record DNSRecord
+	// Name is the fully-qualified name that was looked up.
+	Name   string
+	Status DNSRecordStatus
+	// Actual holds the values published at Name, for missing and mismatched records.
+	Actual  []string
+	Message string
+	Err     error
+}
+
+func (r DNSRecordResult) String() string {
+	result := fmt.Sprintf("%s %s: %s", r.Record.Type, r.Name, r.Status)
+	if r.Message != "" {
+		result += ": " + r.Message
+	}
+	return result
+}
+
+// DNSVerification holds the result of every record checked by DNSVerifier.Verify.
+type DNSVerification []DNSRecordResult
+
+// OK reports whether every record is published as required.
+func (v DNSVerification) OK() bool {
+	return len(v.Problems()) == 0
+}
+
+// Problems returns the results that are not DNSRecordOK.
+func (v DNSVerification) Problems() []DNSRecordResult {
+	var problems []DNSRecordResult
+	for _, result := range v {
+		if result.Status != DNSRecordOK {
+			problems = append(problems, result)
+		}
+	}
+	return problems
+}
+
+// DNSVerifier checks that the records returned by GetDomainRecords are published,
+// so DNS can be fixed before calling ActivateDomain.
+type DNSVerifier struct {
+	Resolver Resolver
+}
+
+// NewDNSVerifier returns a verifier using resolver, or net.DefaultResolver when resolver is nil.
+func NewDNSVerifier(resolver Resolver) *DNSVerifier {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &DNSVerifier{Resolver: resolver}
+}
+
+// Verify looks up every record in records and compares it with what is published.
+// SPF records pass when the published record includes every mechanism Migadu requires,
+// so a record that also authorizes other senders is accepted. CNAME targets are compared
+// with the canonical name the resolver returns, so a chain of CNAMEs reports a mismatch.
+func (v *DNSVerifier) Verify(ctx context.Context, records DomainRecords) DNSVerification {
+	verification := make(DNSVerification, 0, len(records.All()))
+	for _, record := range records.All() {
+		verification = append(verification, v.verifyRecord(ctx, records.DomainName, record))
+	}
+	return verification
+}
+
+// All returns every required record: MX, SPF, DKIM, DMARC and the verification record, in that order.
+func (r DomainRecords) All() []DNSRecord {
+	var all []DNSRecord
+	all = append(all, r.MXRecords...)
+	if r.SPF != nil {
+		all = append(all, *r.SPF)
+	}
+	all = append(all, r.DKIM...)
+	if r.DMARC != nil {
+		all = append(all, *r.DMARC)
+	}
+	if r.DNSVerification != nil {
+		all = append(all, *r.DNSVerification)
+	}
+	return all
+}
+
+// absoluteName returns name as a fully-qualified name without the trailing dot.
+// Names that are empty or "@" refer to domain, and other names not ending in domain are relative to it.
+func absoluteName(name, domain string) string {
+	name = strings.TrimSpace(name)
+	domain = strings.TrimSuffix(strings.TrimSpace(domain), ".")
+	switch {
+	case name == "" || name == "@":
+		return domain
+	case strings.HasSuffix(name, "."):
+		return strings.TrimSuffix(name, ".")
+	case domain == "" || strings.EqualFold(name, domain) || strings.HasSuffix(strings.ToLower(name), "."+strings.ToLower(domain)):
+		return name
+	}
+	return name + "." + domain
+}
+
+func (v *DNSVerifier) verifyRecord(ctx context.Context, domain string, record DNSRecord) DNSRecordResult {
+	result := DNSRecordResult{Record: record, Name: absoluteName(record.Name, domain)}
+	var err error
+	switch strings.ToUpper(record.Type) {
+	case "MX":
+		err = v.verifyMX(ctx, &result)
+	case "CNAME":
+		err = v.verifyCNAME(ctx, &result)
+	case "TXT":
+		err = v.verifyTXT(ctx, &result)
+	default:
+		result.Status = DNSRecordError
+		result.Message = fmt.Sprintf("unsupported record type %q", record.Type)
+		return result
+	}
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		result.Status = DNSRecordMissing
+		result.Message = "no records found"
+	case err != nil:
+		result.Status = DNSRecordError
+		result.Message = err.Error()
+		result.Err = err
+	}
+	return result
+}
+
+func (v *DNSVerifier) verifyMX(ctx context.Context, result *DNSRecordResult) error {
+	mxs, err := v.Resolver.LookupMX(ctx, result.Name)
+	if err != nil {
+		return err
+	}
+	want := normalizeHost(result.Record.Value)
+	for _, mx := range mxs {
+		result.Actual = append(result.Actual, strconv.Itoa(int(mx.Pref))+" "+normalizeHost(mx.Host))
+	}
+	for _, mx := range mxs {
+		if normalizeHost(mx.Host) != want {
+			continue
+		}
+		if result.Record.Priority != nil && int(mx.Pref) != *result.Record.Priority {
+			result.Status = DNSRecordMismatch
+			result.Message = fmt.Sprintf("priority is %d, want %d", mx.Pref, *result.Record.Priority)
+			return nil
+		}
+		result.Status, result.Actual = DNSRecordOK, nil
+		return nil
+	}
+	result.Status = DNSRecordMissing
+	if len(mxs) > 0 {
+		result.Status = DNSRecordMismatch
+	}
+	result.Message = fmt.Sprintf("%s is not an MX host", want)
+	return nil
+}
+
+func (v *DNSVerifier) verifyCNAME(ctx context.Context, result *DNSRecordResult) error {
+	target, err := v.Resolver.LookupCNAME(ctx, result.Name)
+	if err != nil {
+		return err
+	}
+	target = normalizeHost(target)
+	switch target {
+	case normalizeHost(result.Record.Value):
+		result.Status = DNSRecordOK
+	case normalizeHost(result.Name):
+		// The name exists, but not as a CNAME.
+		result.Status = DNSRecordMissing
+		result.Message = "no CNAME record"
+	default:
+		result.Status = DNSRecordMismatch
+		result.Actual = []string{target}
+		result.Message = fmt.Sprintf("points to %s", target)
+	}
+	return nil
+}
+
+func (v *DNSVerifier) verifyTXT(ctx context.Context, result *DNSRecordResult) error {
+	txts, err := v.Resolver.LookupTXT(ctx, result.Name)
+	if err != nil {
+		return err
+	}
+	want := result.Record.Value
+	switch {
+	case hasTXTPrefix(want, "v=spf1"):
+		compareTXT(result, filterTXT(txts, "v=spf1"), "SPF", func(actual string) []string {
+			return missingSPFMechanisms(want, actual)
+		})
+	case hasTXTPrefix(want, "v=DMARC1"):
+		compareTXT(result, filterTXT(txts, "v=DMARC1"), "DMARC", func(actual string) []string {
+			if normalizeTXT(actual) == normalizeTXT(want) {
+				return nil
+			}
+			return []string{want}
+		})
+	default:
+		for _, txt := range txts {
+			if normalizeTXT(txt) == normalizeTXT(want) {
+				result.Status = DNSRecordOK
+				return nil
+			}
+		}
+		result.Status = DNSRecordMissing
+		result.Actual = txts
+		result.Message = fmt.Sprintf("no TXT record %q", want)
+	}
+	return nil
+}
+
+// compareTXT checks a record that must be published exactly once, such as SPF or DMARC.
+// missing returns the parts of the wanted record the actual record lacks.
+func compareTXT(result *DNSRecordResult, actual []string, kind string, missing func(string) []string) {
+	switch len(actual) {
+	case 0:
+		result.Status = DNSRecordMissing
+		result.Message = fmt.Sprintf("no %s record", kind)
+		return
+	case 1:
+	default:
+		result.Status = DNSRecordMismatch
+		result.Actual = actual
+		result.Message = fmt.Sprintf("%d %s records are published, want one", len(actual), kind)
+		return
+	}
+	if parts := missing(actual[0]); len(parts) > 0 {
+		result.Status = DNSRecordMismatch
+		result.Actual = actual
+		result.Message = "missing " + strings.Join(parts, " ")
+		return
+	}
+	result.Status = DNSRecordOK
+}
+
+// missingSPFMechanisms returns the mechanisms of want that actual does not contain. The version
+// and the "all" policy are not compared, because merged records choose their own policy.
+func missingSPFMechanisms(want, actual string) []string {
+	have := map[string]bool{}
+	for _, term := range strings.Fields(strings.ToLower(actual)) {
+		have[strings.TrimLeft(term, "+")] = true
+	}
+	var missing []string
+	for _, term := range strings.Fields(want) {
+		mechanism := strings.TrimLeft(strings.ToLower(term), "+")
+		if mechanism == "v=spf1" || strings.TrimLeft(mechanism, "-~?") == "all" {
+			continue
+		}
+		if !have[mechanism] {
+			missing = append(missing, term)
+		}
+	}
+	return missing
+}
+
+func filterTXT(txts []string, prefix string) []string {
+	var matched []string
+	for _, txt := range txts {
+		if hasTXTPrefix(txt, prefix) {
+			matched = append(matched, txt)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+func hasTXTPrefix(txt, prefix string) bool {
+	txt = strings.TrimSpace(txt)
+	if len(txt) < len(prefix) || !strings.EqualFold(txt[:len(prefix)], prefix) {
+		return false
+	}
+	// "v=spf1" must not match "v=spf10".
+	return len(txt) == len(prefix) || txt[len(prefix)] == ' ' || txt[len(prefix)] == ';'
+}
+
+// normalizeTXT ignores whitespace and a trailing semicolon, which DMARC records often differ in.
+func normalizeTXT(txt string) string {
+	return strings.TrimSuffix(strings.Join(strings.Fields(txt), ""), ";")
+}
+
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+}
@@ -0,0 +1,85 @@
+package migadu_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	migadu "github.com/z-xavier/migadu-go"
+	"github.com/z-xavier/migadu-go/migadutest"
+)
+
+func testRecords(t *testing.T) migadu.DomainRecords {
+	t.Helper()
+	s := migadutest.NewUnstartedServer()
+	s.AddDomain(migadu.Domain{Name: "example.com"})
+	client, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := client.GetDomainRecords(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *records
+}
+
+func TestDNSVerifierAcceptsPublishedRecords(t *testing.T) {
+	records := testRecords(t)
+	resolver := migadutest.NewResolver()
+	resolver.Publish(records)
+	verification := migadu.NewDNSVerifier(resolver).Verify(context.Background(), records)
+	if !verification.OK() || len(verification) != len(records.All()) {
+		t.Fatalf("Verify() = %v", verification)
+	}
+}
+
+func TestDNSVerifierMergesSPF(t *testing.T) {
+	records := testRecords(t)
+	for _, tt := range []struct {
+		published []string
+		want      migadu.DNSRecordStatus
+	}{
+		{published: []string{"v=spf1 include:_spf.google.com include:spf.migadu.com ~all", "hosted-email-verify=x"}, want: migadu.DNSRecordOK},
+		{published: []string{"v=spf1 include:_spf.google.com -all"}, want: migadu.DNSRecordMismatch},
+		{published: []string{"v=spf1 include:spf.migadu.com -all", "v=spf1 -all"}, want: migadu.DNSRecordMismatch},
+		{published: []string{"google-site-verification=abc"}, want: migadu.DNSRecordMissing},
+	} {
+		resolver := migadutest.NewResolver()
+		resolver.AddTXT("example.com", tt.published...)
+		spf := migadu.DomainRecords{DomainName: "example.com", SPF: records.SPF}
+		result := migadu.NewDNSVerifier(resolver).Verify(context.Background(), spf)[0]
+		if result.Status != tt.want {
+			t.Errorf("SPF %q = %v, want %s", tt.published, result, tt.want)
+		}
+	}
+}
+
+func TestDNSVerifierReportsProblems(t *testing.T) {
+	records := testRecords(t)
+	resolver := migadutest.NewResolver()
+	resolver.Publish(records)
+	resolver.Remove("example.com")
+	resolver.AddMX("example.com", "aspmx1.migadu.com", 10)
+	resolver.AddMX("example.com", "aspmx2.migadu.com", 30)
+	resolver.AddTXT("example.com", "v=spf1 include:spf.migadu.com -all")
+	resolver.SetCNAME("key2._domainkey.example.com", "key2.example.com._domainkey.elsewhere.net")
+	resolver.Remove("key3._domainkey.example.com")
+	resolver.SetError("_dmarc.example.com", errors.New("i/o timeout"))
+
+	var got []string
+	for _, problem := range migadu.NewDNSVerifier(resolver).Verify(context.Background(), records).Problems() {
+		got = append(got, problem.Name+" "+string(problem.Status))
+	}
+	want := []string{
+		"example.com mismatch",
+		"key2._domainkey.example.com mismatch",
+		"key3._domainkey.example.com missing",
+		"_dmarc.example.com error",
+		"example.com missing",
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("problems = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+package migadu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidPath is returned by Do for a path segment that is empty, "." or "..", or contains a slash.
+var ErrInvalidPath = errors.New("invalid path segment")
+
+// Do calls an endpoint the typed methods do not cover yet. path holds the
+// segments after /v1, such as []string{"domains", "example.com", "mailboxes"}.
+// Slashes around a segment are trimmed and each segment is escaped; a segment
+// that is empty, "." or "..", or still contains a slash fails with ErrInvalidPath.
+// A non-nil body is sent as JSON, and a successful response is decoded into out unless out is nil.
+//
+// Do shares authentication, the timeout, retries, middleware, dry-run mode,
+// restrictions and APIError handling with the typed methods. A client restricted
+// with RestrictToDomains cannot call the domain list itself, which Do cannot
+// filter; ListDomains returns the allowed domains instead.
+func (c *Client) Do(ctx context.Context, method string, path []string, body, out any) error {
+	path, err := normalizePath(path)
+	if err != nil {
+		return err
+	}
+	if len(path) > 0 && strings.EqualFold(path[0], domainsPath) {
+		if len(path) > 1 {
+			if err := c.checkDomain(path[1]); err != nil {
+				return err
+			}
+		} else if c.allowedDomains != nil {
+			return &RestrictionError{Method: method, Err: ErrDomainNotAllowed}
+		}
+	}
+	builder := c.getV1ReqBuilder().SetMethod(method)
+	for _, segment := range path {
+		builder.AddPath(segment)
+	}
+	if body != nil {
+		builder.SetHeaderContentTypeJson().SetBodyJson(body)
+	}
+	req, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	var schema reflect.Type
+	if out != nil {
+		schema = reflect.TypeOf(out)
+	}
+	return c.call(ctx, req, schema, func(body *responseReader) error {
+		if out == nil {
+			return nil
+		}
+		// A retried attempt decodes again from scratch.
+		if value := reflect.ValueOf(out); value.Kind() == reflect.Pointer && !value.IsNil() {
+			value.Elem().SetZero()
+		}
+		return decodeJSON(body, out)
+	})
+}
+
+// normalizePath trims the slashes the request builder would strip, so that
+// restrictions are checked against the path that is actually sent. Dot segments
+// are rejected because a server or proxy may resolve them to another domain.
+func normalizePath(path []string) ([]string, error) {
+	normalized := make([]string, len(path))
+	for i, segment := range path {
+		trimmed := strings.Trim(segment, "/")
+		if strings.TrimSpace(trimmed) == "" || trimmed == "." || trimmed == ".." || strings.Contains(trimmed, "/") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPath, segment)
+		}
+		normalized[i] = trimmed
+	}
+	return normalized, nil
+}
@@ -0,0 +1,110 @@
+package migadu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDoSendsAuthenticatedJSON(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			got = req
+			data, _ := io.ReadAll(req.Body)
+			gotBody = string(data)
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"state":"queued"}`))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]json.RawMessage
+	err = client.Do(context.Background(), http.MethodPost, []string{"domains", "example.com", "mailboxes", "demo", "move to"}, map[string]string{"target": "other"}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.URL.EscapedPath() != "/v1/domains/example.com/mailboxes/demo/move%20to" {
+		t.Fatalf("path = %s", got.URL.EscapedPath())
+	}
+	if email, key, ok := got.BasicAuth(); !ok || email != "admin@example.com" || key != "secret" {
+		t.Fatal("request is not authenticated")
+	}
+	if gotBody != `{"target":"other"}` || got.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("body = %s, content type = %q", gotBody, got.Header.Get("Content-Type"))
+	}
+	if string(out["state"]) != `"queued"` {
+		t.Fatalf("out = %v", out)
+	}
+}
+
+func TestDoReturnsAPIErrorsAndRestrictions(t *testing.T) {
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{"error":"not_found"}`))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err = client.Do(ctx, http.MethodGet, []string{"domains", "example.com", "unknown"}, nil, nil); !IsNotFound(err) {
+		t.Fatalf("Do() error = %v", err)
+	}
+	restricted := client.RestrictToDomains("example.com").ReadOnly()
+	if err = restricted.Do(ctx, http.MethodGet, []string{"domains", "other.example"}, nil, nil); !errors.Is(err, ErrDomainNotAllowed) {
+		t.Fatalf("Do() on other domain error = %v", err)
+	}
+	if err = restricted.Do(ctx, http.MethodDelete, []string{"domains", "example.com", "unknown"}, nil, nil); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("Do() delete error = %v", err)
+	}
+}
+
+func TestDoChecksRestrictionsOnTheNormalizedPath(t *testing.T) {
+	var sent []string
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			sent = append(sent, req.Method+" "+req.URL.EscapedPath())
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	restricted := client.RestrictToDomains("example.com")
+	if err = restricted.Do(ctx, http.MethodDelete, []string{"/domains", "other.com/", "aliases", "x"}, nil, nil); !errors.Is(err, ErrDomainNotAllowed) {
+		t.Fatalf("Do() with slashes error = %v", err)
+	}
+	for _, path := range [][]string{
+		{"domains", "", "aliases"},
+		{"domains", "/"},
+		{"domains", "example.com/../other.com", "aliases"},
+		{"domains", "example.com", "..", "other.com", "mailboxes"},
+		{".", "domains", "other.com"},
+		{"domains", "example.com", "/./", "aliases"},
+	} {
+		if err = restricted.Do(ctx, http.MethodGet, path, nil, nil); !errors.Is(err, ErrInvalidPath) {
+			t.Fatalf("Do(%q) error = %v", path, err)
+		}
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		if err = restricted.Do(ctx, method, []string{"domains"}, nil, nil); !errors.Is(err, ErrDomainNotAllowed) {
+			t.Fatalf("Do(%s domains) error = %v", method, err)
+		}
+	}
+	if len(sent) != 0 {
+		t.Fatalf("sent = %q", sent)
+	}
+	if err = restricted.Do(ctx, http.MethodGet, []string{"/domains/", "example.com"}, nil, nil); err != nil || sent[0] != "GET /v1/domains/example.com" {
+		t.Fatalf("Do() = %v, sent %q", err, sent)
+	}
+}
@@ -59,106 +59,138 @@ func (s *stringValue) UnmarshalJSON(data []byte) error {
 
 // Domain represents a domain in the Migadu API.
 type Domain struct {
-	Name                             string   `json:"name,omitempty"`
-	ActivatedAt                      *string  `json:"activated_at,omitempty"`
-	DeactivatedAt                    *string  `json:"deactivated_at,omitempty"`
-	Tags                             []string `json:"tags,omitempty"`
-	State                            string   `json:"state,omitempty"`
-	Description                      string   `json:"description,omitempty"`
-	CanSend                          bool     `json:"can_send,omitempty"`
-	CanReceive                       bool     `json:"can_receive,omitempty"`
-	CanAccess                        bool     `json:"can_access,omitempty"`
-	MXProxyEnabled                   bool     `json:"mx_proxy_enabled,omitempty"`
-	SpamAggressiveness               string   `json:"spam_aggressiveness,omitempty"`
-	SubjectRewritingEnabled          bool     `json:"subject_rewriting_enabled,omitempty"`
-	JunkSubjectKeywordSpam           bool     `json:"junk_subject_keyword_spam,omitempty"`
-	SenderDenylist                   []string `json:"sender_denylist,omitempty"`
-	SenderAllowlist                  []string `json:"sender_allowlist,omitempty"`
-	RecipientDenylist                []string `json:"recipient_denylist,omitempty"`
-	CatchallDestinations             []string `json:"catchall_destinations,omitempty"`
-	HostedDNS                        bool     `json:"hosted_dns,omitempty"`
-	MailboxDefaultIncomingLimit      int      `json:"mailbox_default_incoming_limit,omitempty"`
-	MailboxDefaultOutgoingLimit      int      `json:"mailbox_default_outgoing_limit,omitempty"`
-	MailboxDefaultStorageLimit       int      `json:"mailbox_default_storage_limit,omitempty"`
-	MailboxDefaultSendingEnabled     bool     `json:"mailbox_default_sending_enabled,omitempty"`
-	MailboxDefaultReceivingEnabled   bool     `json:"mailbox_default_receiving_enabled,omitempty"`
-	MailboxDefaultImapEnabled        bool     `json:"mailbox_default_imap_enabled,omitempty"`
-	MailboxDefaultPop3Enabled        bool     `json:"mailbox_default_pop3_enabled,omitempty"`
-	MailboxDefaultManagesieveEnabled bool     `json:"mailbox_default_managesieve_enabled,omitempty"`
+	Name                             string             `json:"name,omitempty"`
+	ActivatedAt                      *Timestamp         `json:"activated_at,omitempty"`
+	DeactivatedAt                    *Timestamp         `json:"deactivated_at,omitempty"`
+	Tags                             []string           `json:"tags,omitempty"`
+	State                            DomainState        `json:"state,omitempty"`
+	Description                      string             `json:"description,omitempty"`
+	CanSend                          bool               `json:"can_send,omitempty"`
+	CanReceive                       bool               `json:"can_receive,omitempty"`
+	CanAccess                        bool               `json:"can_access,omitempty"`
+	MXProxyEnabled                   bool               `json:"mx_proxy_enabled,omitempty"`
+	SpamAggressiveness               SpamAggressiveness `json:"spam_aggressiveness,omitempty"`
+	SubjectRewritingEnabled          bool               `json:"subject_rewriting_enabled,omitempty"`
+	JunkSubjectKeywordSpam           bool               `json:"junk_subject_keyword_spam,omitempty"`
+	SenderDenylist                   []string           `json:"sender_denylist,omitempty"`
+	SenderAllowlist                  []string           `json:"sender_allowlist,omitempty"`
+	RecipientDenylist                []string           `json:"recipient_denylist,omitempty"`
+	CatchallDestinations             []string           `json:"catchall_destinations,omitempty"`
+	HostedDNS                        bool               `json:"hosted_dns,omitempty"`
+	MailboxDefaultIncomingLimit      int                `json:"mailbox_default_incoming_limit,omitempty"`
+	MailboxDefaultOutgoingLimit      int                `json:"mailbox_default_outgoing_limit,omitempty"`
+	MailboxDefaultStorageLimit       int                `json:"mailbox_default_storage_limit,omitempty"`
+	MailboxDefaultSendingEnabled     bool               `json:"mailbox_default_sending_enabled,omitempty"`
+	MailboxDefaultReceivingEnabled   bool               `json:"mailbox_default_receiving_enabled,omitempty"`
+	MailboxDefaultImapEnabled        bool               `json:"mailbox_default_imap_enabled,omitempty"`
+	MailboxDefaultPop3Enabled        bool               `json:"mailbox_default_pop3_enabled,omitempty"`
+	MailboxDefaultManagesieveEnabled bool               `json:"mailbox_default_managesieve_enabled,omitempty"`
+	// Extra holds JSON fields this version of the library does not model.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
-// UnmarshalJSON accepts the alternate string and numeric representations shown in Migadu's domain documentation.
+// UnmarshalJSON accepts the alternate string and numeric representations shown in Migadu's domain
+// documentation, and keeps unknown fields in Extra.
 func (d *Domain) UnmarshalJSON(data []byte) error {
-	type domainAlias Domain
+	type plain Domain
 	wire := struct {
-		*domainAlias
-		Tags                 stringList  `json:"tags"`
-		SpamAggressiveness   stringValue `json:"spam_aggressiveness"`
-		SenderDenylist       stringList  `json:"sender_denylist"`
-		SenderAllowlist      stringList  `json:"sender_allowlist"`
-		RecipientDenylist    stringList  `json:"recipient_denylist"`
-		CatchallDestinations stringList  `json:"catchall_destinations"`
-	}{domainAlias: (*domainAlias)(d)}
+		*plain
+		Tags                 stringList `json:"tags"`
+		SenderDenylist       stringList `json:"sender_denylist"`
+		SenderAllowlist      stringList `json:"sender_allowlist"`
+		RecipientDenylist    stringList `json:"recipient_denylist"`
+		CatchallDestinations stringList `json:"catchall_destinations"`
+	}{plain: (*plain)(d)}
 	if err := json.Unmarshal(data, &wire); err != nil {
 		return err
 	}
 	d.Tags = []string(wire.Tags)
-	d.SpamAggressiveness = string(wire.SpamAggressiveness)
 	d.SenderDenylist = []string(wire.SenderDenylist)
 	d.SenderAllowlist = []string(wire.SenderAllowlist)
 	d.RecipientDenylist = []string(wire.RecipientDenylist)
 	d.CatchallDestinations = []string(wire.CatchallDestinations)
-	return nil
+	extra, err := extraFields(data, wire.plain)
+	d.Extra = extra
+	return err
+}
+
+// MarshalJSON writes Extra back next to the known fields.
+func (d Domain) MarshalJSON() ([]byte, error) {
+	type plain Domain
+	return marshalWithExtra((*plain)(&d), d.Extra)
 }
 
 // CreateDomainRequest contains fields accepted by the domain create endpoint.
 type CreateDomainRequest struct {
-	Name                             string   `json:"name"`
-	CreateDefaultAddresses           *bool    `json:"create_default_addresses,omitempty"`
-	Tags                             []string `json:"tags,omitempty"`
-	Description                      string   `json:"description,omitempty"`
-	CanAccess                        *bool    `json:"can_access,omitempty"`
-	MXProxyEnabled                   *bool    `json:"mx_proxy_enabled,omitempty"`
-	SpamAggressiveness               string   `json:"spam_aggressiveness,omitempty"`
-	SubjectRewritingEnabled          *bool    `json:"subject_rewriting_enabled,omitempty"`
-	JunkSubjectKeywordSpam           *bool    `json:"junk_subject_keyword_spam,omitempty"`
-	SenderDenylist                   []string `json:"sender_denylist,omitempty"`
-	SenderAllowlist                  []string `json:"sender_allowlist,omitempty"`
-	RecipientDenylist                []string `json:"recipient_denylist,omitempty"`
-	CatchallDestinations             []string `json:"catchall_destinations,omitempty"`
-	HostedDNS                        *bool    `json:"hosted_dns,omitempty"`
-	MailboxDefaultIncomingLimit      *int     `json:"mailbox_default_incoming_limit,omitempty"`
-	MailboxDefaultOutgoingLimit      *int     `json:"mailbox_default_outgoing_limit,omitempty"`
-	MailboxDefaultStorageLimit       *int     `json:"mailbox_default_storage_limit,omitempty"`
-	MailboxDefaultSendingEnabled     *bool    `json:"mailbox_default_sending_enabled,omitempty"`
-	MailboxDefaultReceivingEnabled   *bool    `json:"mailbox_default_receiving_enabled,omitempty"`
-	MailboxDefaultImapEnabled        *bool    `json:"mailbox_default_imap_enabled,omitempty"`
-	MailboxDefaultPop3Enabled        *bool    `json:"mailbox_default_pop3_enabled,omitempty"`
-	MailboxDefaultManagesieveEnabled *bool    `json:"mailbox_default_managesieve_enabled,omitempty"`
+	Name                             string             `json:"name"`
+	CreateDefaultAddresses           *bool              `json:"create_default_addresses,omitempty"`
+	Tags                             []string           `json:"tags,omitempty"`
+	Description                      string             `json:"description,omitempty"`
+	CanAccess                        *bool              `json:"can_access,omitempty"`
+	MXProxyEnabled                   *bool              `json:"mx_proxy_enabled,omitempty"`
+	SpamAggressiveness               SpamAggressiveness `json:"spam_aggressiveness,omitempty"`
+	SubjectRewritingEnabled          *bool              `json:"subject_rewriting_enabled,omitempty"`
+	JunkSubjectKeywordSpam           *bool              `json:"junk_subject_keyword_spam,omitempty"`
+	SenderDenylist                   []string           `json:"sender_denylist,omitempty"`
+	SenderAllowlist                  []string           `json:"sender_allowlist,omitempty"`
+	RecipientDenylist                []string           `json:"recipient_denylist,omitempty"`
+	CatchallDestinations             []string           `json:"catchall_destinations,omitempty"`
+	HostedDNS                        *bool              `json:"hosted_dns,omitempty"`
+	MailboxDefaultIncomingLimit      *int               `json:"mailbox_default_incoming_limit,omitempty"`
+	MailboxDefaultOutgoingLimit      *int               `json:"mailbox_default_outgoing_limit,omitempty"`
+	MailboxDefaultStorageLimit       *int               `json:"mailbox_default_storage_limit,omitempty"`
+	MailboxDefaultSendingEnabled     *bool              `json:"mailbox_default_sending_enabled,omitempty"`
+	MailboxDefaultReceivingEnabled   *bool              `json:"mailbox_default_receiving_enabled,omitempty"`
+	MailboxDefaultImapEnabled        *bool              `json:"mailbox_default_imap_enabled,omitempty"`
+	MailboxDefaultPop3Enabled        *bool              `json:"mailbox_default_pop3_enabled,omitempty"`
+	MailboxDefaultManagesieveEnabled *bool              `json:"mailbox_default_managesieve_enabled,omitempty"`
 }
 
 // UpdateDomainRequest uses pointers so zero values can be sent explicitly.
 type UpdateDomainRequest struct {
-	Tags                             *[]string `json:"tags,omitempty"`
-	Description                      *string   `json:"description,omitempty"`
-	CanAccess                        *bool     `json:"can_access,omitempty"`
-	MXProxyEnabled                   *bool     `json:"mx_proxy_enabled,omitempty"`
-	SpamAggressiveness               *string   `json:"spam_aggressiveness,omitempty"`
-	SubjectRewritingEnabled          *bool     `json:"subject_rewriting_enabled,omitempty"`
-	JunkSubjectKeywordSpam           *bool     `json:"junk_subject_keyword_spam,omitempty"`
-	SenderDenylist                   *[]string `json:"sender_denylist,omitempty"`
-	SenderAllowlist                  *[]string `json:"sender_allowlist,omitempty"`
-	RecipientDenylist                *[]string `json:"recipient_denylist,omitempty"`
-	CatchallDestinations             *[]string `json:"catchall_destinations,omitempty"`
-	HostedDNS                        *bool     `json:"hosted_dns,omitempty"`
-	MailboxDefaultIncomingLimit      *int      `json:"mailbox_default_incoming_limit,omitempty"`
-	MailboxDefaultOutgoingLimit      *int      `json:"mailbox_default_outgoing_limit,omitempty"`
-	MailboxDefaultStorageLimit       *int      `json:"mailbox_default_storage_limit,omitempty"`
-	MailboxDefaultSendingEnabled     *bool     `json:"mailbox_default_sending_enabled,omitempty"`
-	MailboxDefaultReceivingEnabled   *bool     `json:"mailbox_default_receiving_enabled,omitempty"`
-	MailboxDefaultImapEnabled        *bool     `json:"mailbox_default_imap_enabled,omitempty"`
-	MailboxDefaultPop3Enabled        *bool     `json:"mailbox_default_pop3_enabled,omitempty"`
-	MailboxDefaultManagesieveEnabled *bool     `json:"mailbox_default_managesieve_enabled,omitempty"`
+	Tags                             *[]string           `json:"tags,omitempty"`
+	Description                      *string             `json:"description,omitempty"`
+	CanAccess                        *bool               `json:"can_access,omitempty"`
+	MXProxyEnabled                   *bool               `json:"mx_proxy_enabled,omitempty"`
+	SpamAggressiveness               *SpamAggressiveness `json:"spam_aggressiveness,omitempty"`
+	SubjectRewritingEnabled          *bool               `json:"subject_rewriting_enabled,omitempty"`
+	JunkSubjectKeywordSpam           *bool               `json:"junk_subject_keyword_spam,omitempty"`
+	SenderDenylist                   *[]string           `json:"sender_denylist,omitempty"`
+	SenderAllowlist                  *[]string           `json:"sender_allowlist,omitempty"`
+	RecipientDenylist                *[]string           `json:"recipient_denylist,omitempty"`
+	CatchallDestinations             *[]string           `json:"catchall_destinations,omitempty"`
+	HostedDNS                        *bool               `json:"hosted_dns,omitempty"`
+	MailboxDefaultIncomingLimit      *int                `json:"mailbox_default_incoming_limit,omitempty"`
+	MailboxDefaultOutgoingLimit      *int                `json:"mailbox_default_outgoing_limit,omitempty"`
+	MailboxDefaultStorageLimit       *int                `json:"mailbox_default_storage_limit,omitempty"`
+	MailboxDefaultSendingEnabled     *bool               `json:"mailbox_default_sending_enabled,omitempty"`
+	MailboxDefaultReceivingEnabled   *bool               `json:"mailbox_default_receiving_enabled,omitempty"`
+	MailboxDefaultImapEnabled        *bool               `json:"mailbox_default_imap_enabled,omitempty"`
+	MailboxDefaultPop3Enabled        *bool               `json:"mailbox_default_pop3_enabled,omitempty"`
+	MailboxDefaultManagesieveEnabled *bool               `json:"mailbox_default_managesieve_enabled,omitempty"`
+}
+
+// Validate checks the request locally. It returns a *ValidationError listing every invalid field.
+func (r CreateDomainRequest) Validate() error {
+	var v validator
+	v.domainName("name", r.Name)
+	v.enum("spam_aggressiveness", r.SpamAggressiveness)
+	v.addresses("catchall_destinations", r.CatchallDestinations, false)
+	v.nonNegative("mailbox_default_incoming_limit", r.MailboxDefaultIncomingLimit)
+	v.nonNegative("mailbox_default_outgoing_limit", r.MailboxDefaultOutgoingLimit)
+	v.nonNegative("mailbox_default_storage_limit", r.MailboxDefaultStorageLimit)
+	return v.err()
+}
+
+// Validate checks the fields that are set. It returns a *ValidationError listing every invalid field.
+func (r UpdateDomainRequest) Validate() error {
+	var v validator
+	v.enum("spam_aggressiveness", deref(r.SpamAggressiveness))
+	v.addresses("catchall_destinations", deref(r.CatchallDestinations), false)
+	v.nonNegative("mailbox_default_incoming_limit", r.MailboxDefaultIncomingLimit)
+	v.nonNegative("mailbox_default_outgoing_limit", r.MailboxDefaultOutgoingLimit)
+	v.nonNegative("mailbox_default_storage_limit", r.MailboxDefaultStorageLimit)
+	return v.err()
 }
 
 // DNSRecord represents a DNS record returned for a domain.
@@ -167,6 +199,22 @@ type DNSRecord struct {
 	Priority *int   `json:"priority,omitempty"`
 	Type     string `json:"type,omitempty"`
 	Value    string `json:"value,omitempty"`
+	// Extra holds JSON fields this version of the library does not model.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON keeps unknown fields in Extra.
+func (d *DNSRecord) UnmarshalJSON(data []byte) error {
+	type plain DNSRecord
+	extra, err := unmarshalWithExtra(data, (*plain)(d))
+	d.Extra = extra
+	return err
+}
+
+// MarshalJSON writes Extra back next to the known fields.
+func (d DNSRecord) MarshalJSON() ([]byte, error) {
+	type plain DNSRecord
+	return marshalWithExtra((*plain)(&d), d.Extra)
 }
 
 // DomainRecords contains the DNS records required by Migadu.
@@ -177,9 +225,26 @@ type DomainRecords struct {
 	DNSVerification *DNSRecord  `json:"dns_verification,omitempty"`
 	MXRecords       []DNSRecord `json:"mx_records,omitempty"`
 	SPF             *DNSRecord  `json:"spf,omitempty"`
+	// Extra holds JSON fields this version of the library does not model.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON keeps unknown fields in Extra.
+func (d *DomainRecords) UnmarshalJSON(data []byte) error {
+	type plain DomainRecords
+	extra, err := unmarshalWithExtra(data, (*plain)(d))
+	d.Extra = extra
+	return err
+}
+
+// MarshalJSON writes Extra back next to the known fields.
+func (d DomainRecords) MarshalJSON() ([]byte, error) {
+	type plain DomainRecords
+	return marshalWithExtra((*plain)(&d), d.Extra)
 }
 
 // DomainDiagnostics is intentionally open because Migadu does not document its response schema.
+// Use Checks, FailingChecks and AllPassing for a typed view.
 type DomainDiagnostics map[string]any
 
 // DomainUsage represents message and storage usage for a domain.
@@ -188,6 +253,22 @@ type DomainUsage struct {
 	Incoming   int     `json:"incoming,omitempty"`
 	Outgoing   int     `json:"outgoing,omitempty"`
 	Storage    float64 `json:"storage,omitempty"`
+	// Extra holds JSON fields this version of the library does not model.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON keeps unknown fields in Extra.
+func (d *DomainUsage) UnmarshalJSON(data []byte) error {
+	type plain DomainUsage
+	extra, err := unmarshalWithExtra(data, (*plain)(d))
+	d.Extra = extra
+	return err
+}
+
+// MarshalJSON writes Extra back next to the known fields.
+func (d DomainUsage) MarshalJSON() ([]byte, error) {
+	type plain DomainUsage
+	return marshalWithExtra((*plain)(&d), d.Extra)
 }
 
 // ListDomains lists all domains visible to the authenticated account.
@@ -196,13 +277,11 @@ func (c *Client) ListDomains(ctx context.Context) ([]*Domain, error) {
 	if err != nil {
 		return nil, err
 	}
-	resp, err := doRequest[struct {
-		Domains []*Domain `json:"domains"`
-	}](c, ctx, req)
+	items, err := doListRequest[Domain](c, ctx, req, "domains")
 	if err != nil {
 		return nil, err
 	}
-	return resp.Domains, nil
+	return c.filterDomains(items), nil
 }
 
 // GetDomain retrieves a domain by name.
@@ -220,6 +299,12 @@ func (c *Client) GetDomain(ctx context.Context, domain string) (*Domain, error)
 
 // CreateDomain creates a domain.
 func (c *Client) CreateDomain(ctx context.Context, domain CreateDomainRequest) (*Domain, error) {
+	if err := c.validate(domain); err != nil {
+		return nil, err
+	}
+	if c.allowedDomains != nil {
+		return nil, &RestrictionError{Method: http.MethodPost, Domain: domain.Name, Err: ErrDomainNotAllowed}
+	}
 	req, err := c.getV1ReqBuilder().
 		SetMethod(http.MethodPost).
 		AddPath(domainsPath).
@@ -234,6 +319,9 @@ func (c *Client) CreateDomain(ctx context.Context, domain CreateDomainRequest) (
 
 // UpdateDomain updates only fields explicitly set on update.
 func (c *Client) UpdateDomain(ctx context.Context, domain string, update UpdateDomainRequest) (*Domain, error) {
+	if err := c.validate(update); err != nil {
+		return nil, err
+	}
 	builder, err := c.getDomainReqBuilder(domain)
 	if err != nil {
 		return nil, err
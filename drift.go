@@ -0,0 +1,260 @@
+package migadu
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// DriftKind classifies a difference between a response and the library's model.
+type DriftKind string
+
+const (
+	// DriftUnknownField is a response field the model has no field for. Its value is kept in Extra.
+	DriftUnknownField DriftKind = "unknown_field"
+	// DriftTypeMismatch is a response value whose JSON type differs from the model,
+	// including shapes the library silently accepts, such as a comma-separated string for a list
+	// or a date it could not parse.
+	DriftTypeMismatch DriftKind = "type_mismatch"
+	// DriftMissingField is a modeled field the response did not include.
+	DriftMissingField DriftKind = "missing_field"
+)
+
+// SchemaDrift describes one difference between a response body and the type it was decoded into.
+type SchemaDrift struct {
+	Kind   DriftKind
+	Method string
+	Path   string
+	// Type is the name of the Go type holding Field, empty for anonymous response envelopes.
+	Type string
+	// Field is the location in the response body, such as "mailboxes[2].identities[0].name".
+	Field  string
+	Detail string
+}
+
+func (d SchemaDrift) String() string {
+	return fmt.Sprintf("%s %s: %s %s: %s", d.Method, d.Path, d.Kind, d.Field, d.Detail)
+}
+
+// WithSchemaDriftHandler enables strict decoding: every successful response is
+// compared with the model and each difference is passed to handler. Calls still
+// succeed, so this is meant for monitoring, for example a nightly run against a test account.
+func WithSchemaDriftHandler(handler func(SchemaDrift)) Option {
+	return func(c *Client) {
+		c.schemaDriftHandler = handler
+	}
+}
+
+// DriftReport collects schema drift from any number of calls. It is safe for concurrent use.
+//
+//	report := migadu.NewDriftReport()
+//	client = client.With(migadu.WithSchemaDriftHandler(report.Record))
+type DriftReport struct {
+	mu     sync.Mutex
+	drifts []SchemaDrift
+}
+
+// NewDriftReport returns an empty report.
+func NewDriftReport() *DriftReport {
+	return &DriftReport{}
+}
+
+// Record adds drift to the report. Pass it to WithSchemaDriftHandler.
+func (r *DriftReport) Record(drift SchemaDrift) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.drifts = append(r.drifts, drift)
+}
+
+// Drifts returns a copy of the recorded drift in the order it was found.
+func (r *DriftReport) Drifts() []SchemaDrift {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]SchemaDrift(nil), r.drifts...)
+}
+
+// writeOnlyFields are accepted by the API but never returned, so their absence is not drift.
+var writeOnlyFields = map[string]bool{"password": true}
+
+// listSchema describes a list response envelope such as {"mailboxes": [...]}.
+func listSchema(key string, elem reflect.Type) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{{
+		Name: "Items",
+		Type: reflect.SliceOf(reflect.PointerTo(elem)),
+		Tag:  reflect.StructTag(fmt.Sprintf(`json:%q`, key)),
+	}})
+}
+
+// checkDrift wraps decode so the body is also compared with schema once it decoded successfully.
+func (c *Client) checkDrift(req *http.Request, schema reflect.Type, decode func(*responseReader) error) func(*responseReader) error {
+	if c.schemaDriftHandler == nil || schema == nil {
+		return decode
+	}
+	return func(body *responseReader) error {
+		var raw bytes.Buffer
+		teed := newResponseReader(io.TeeReader(body, &raw), 0)
+		if err := decode(teed); err != nil {
+			return err
+		}
+		_, _ = io.Copy(io.Discard, teed)
+		decoder := json.NewDecoder(&raw)
+		decoder.UseNumber()
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return nil
+		}
+		report := func(drift SchemaDrift) {
+			drift.Method = req.Method
+			drift.Path = req.URL.Path
+			c.schemaDriftHandler(drift)
+		}
+		compareSchema("", "", value, schema, report)
+		return nil
+	}
+}
+
+var (
+	rawMessageType      = reflect.TypeFor[json.RawMessage]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+func compareSchema(path, owner string, value any, t reflect.Type, report func(SchemaDrift)) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if value == nil || t == rawMessageType || t.Kind() == reflect.Interface {
+		return
+	}
+	mismatch := func(want string) {
+		report(SchemaDrift{Kind: DriftTypeMismatch, Type: owner, Field: path, Detail: fmt.Sprintf("got %s, want %s", jsonKind(value), want)})
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		// Types such as Date and Timestamp are encoded as strings.
+		text, ok := value.(string)
+		if !ok {
+			mismatch("string")
+		} else if err := reflect.New(t).Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+			// Decoding kept the text in Raw instead of failing.
+			report(SchemaDrift{Kind: DriftTypeMismatch, Type: owner, Field: path, Detail: err.Error()})
+		}
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			mismatch("object")
+			return
+		}
+		compareStruct(path, object, t, report)
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]any)
+		if !ok {
+			mismatch("array")
+			return
+		}
+		for i, item := range items {
+			compareSchema(fmt.Sprintf("%s[%d]", path, i), owner, item, t.Elem(), report)
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok {
+			mismatch("object")
+			return
+		}
+		for key, item := range object {
+			compareSchema(joinField(path, key), owner, item, t.Elem(), report)
+		}
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			mismatch("string")
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			mismatch("boolean")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if number, ok := value.(json.Number); !ok {
+			mismatch("integer")
+		} else if _, err := number.Int64(); err != nil {
+			mismatch("integer")
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := value.(json.Number); !ok {
+			mismatch("number")
+		}
+	}
+}
+
+func compareStruct(path string, object map[string]any, t reflect.Type, report func(SchemaDrift)) {
+	seen := map[string]bool{}
+	for key, item := range object {
+		field, ok := fieldByJSONName(t, key)
+		if !ok {
+			report(SchemaDrift{Kind: DriftUnknownField, Type: t.Name(), Field: joinField(path, key), Detail: fmt.Sprintf("got %s", jsonKind(item))})
+			continue
+		}
+		seen[strings.ToLower(jsonName(field))] = true
+		compareSchema(joinField(path, key), t.Name(), item, field.Type, report)
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonName(field)
+		if name == "" || seen[strings.ToLower(name)] || writeOnlyFields[name] {
+			continue
+		}
+		report(SchemaDrift{Kind: DriftMissingField, Type: t.Name(), Field: joinField(path, name), Detail: "not in response"})
+	}
+}
+
+// jsonName returns the JSON member name of an exported field, or "" when it is not encoded.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" || !field.IsExported() {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func fieldByJSONName(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name := jsonName(field); name != "" && strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func joinField(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func jsonKind(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case []any:
+		return "array"
+	}
+	return "object"
+}
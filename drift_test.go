@@ -0,0 +1,71 @@
+package migadu
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSchemaDriftIsReportedWithoutFailingTheCall(t *testing.T) {
+	body := `{"name":"example.com","tags":"a, b","spam_aggressiveness":2,"plan":"micro","activated_at":"last week"}`
+	report := NewDriftReport()
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithSchemaDriftHandler(report.Record),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	domain, err := client.GetDomain(context.Background(), "example.com")
+	if err != nil || len(domain.Tags) != 2 || domain.SpamAggressiveness != SpamAggressivenessStricter || domain.ActivatedAt.Raw() != "last week" {
+		t.Fatalf("GetDomain() = %+v, %v", domain, err)
+	}
+	got := map[string]string{}
+	for _, drift := range report.Drifts() {
+		if drift.Method != http.MethodGet || drift.Path != "/v1/domains/example.com" || drift.Type != "Domain" {
+			t.Fatalf("drift = %+v", drift)
+		}
+		got[drift.Field] = string(drift.Kind) + ": " + drift.Detail
+	}
+	for field, want := range map[string]string{
+		"tags":                "type_mismatch: got string, want array",
+		"spam_aggressiveness": "type_mismatch: got number, want string",
+		"plan":                "unknown_field: got string",
+		"activated_at":        `type_mismatch: invalid timestamp "last week", want RFC 3339`,
+		"state":               "missing_field: not in response",
+	} {
+		if got[field] != want {
+			t.Errorf("drift for %s = %q, want %q", field, got[field], want)
+		}
+	}
+	if _, ok := got["name"]; ok {
+		t.Errorf("matching field reported: %v", got["name"])
+	}
+}
+
+func TestSchemaDriftInListResponses(t *testing.T) {
+	report := NewDriftReport()
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithSchemaDriftHandler(report.Record),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{"forwardings":[{"address":"a@example.net","blocked_at":null,"confirmation_sent_at":"","confirmed_at":"","expires_on":"","is_active":true,"remove_upon_expiry":false,"priority":1}]}`
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.ListForwardings(context.Background(), "example.com", "demo"); err != nil {
+		t.Fatal(err)
+	}
+	drifts := report.Drifts()
+	if len(drifts) != 1 || drifts[0].Kind != DriftUnknownField || drifts[0].Field != "forwardings[0].priority" || drifts[0].Type != "Forwarding" {
+		t.Fatalf("drifts = %v", drifts)
+	}
+}
@@ -0,0 +1,146 @@
+package migadu
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// PlannedRequest is a mutation recorded instead of sent in dry-run mode.
+type PlannedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Plan collects the mutations a dry-run client would have sent. It is safe for concurrent use.
+type Plan struct {
+	mu       sync.Mutex
+	requests []PlannedRequest
+}
+
+// NewPlan creates an empty plan for WithDryRun.
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+// Requests returns the planned mutations in the order they were made.
+func (p *Plan) Requests() []PlannedRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	requests := make([]PlannedRequest, len(p.requests))
+	copy(requests, p.requests)
+	return requests
+}
+
+// Reset discards every planned mutation.
+func (p *Plan) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = nil
+}
+
+// add records req and returns its body.
+func (p *Plan) add(req *http.Request) ([]byte, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+	}
+	planned := PlannedRequest{Method: req.Method, Path: req.URL.Path}
+	if len(body) > 0 {
+		planned.Body = json.RawMessage(body)
+	}
+	p.mu.Lock()
+	p.requests = append(p.requests, planned)
+	p.mu.Unlock()
+	return body, nil
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// isMutation reports whether req changes the account. Domain activation is a GET
+// to /domains/{domain}/activate, so the method alone does not tell.
+func isMutation(req *http.Request) bool {
+	if !isReadMethod(req.Method) {
+		return true
+	}
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	n := len(segments)
+	return n >= 3 && strings.EqualFold(segments[n-1], "activate") && strings.EqualFold(segments[n-3], domainsPath)
+}
+
+// plan records req in c's dry-run plan and returns the response the API would
+// likely have sent. Updates (PUT and PATCH) and domain activation first read the
+// resource they change, as any other GET, and fail as that read fails; the result
+// is that resource with the update body, or an active state, applied on top.
+// Creates return their request body and deletes an empty body.
+func (c *Client) plan(ctx context.Context, req *http.Request) ([]byte, error) {
+	var current []byte
+	activation := isReadMethod(req.Method)
+	if activation || req.Method == http.MethodPut || req.Method == http.MethodPatch {
+		read := req.Clone(ctx)
+		read.Method = http.MethodGet
+		read.Body, read.GetBody, read.ContentLength = nil, nil, 0
+		read.Header.Del("Content-Type")
+		if activation {
+			read.URL.Path, read.URL.RawPath = path.Dir(read.URL.Path), ""
+		}
+		err := c.sendWithRetry(ctx, read, func(body *responseReader) error {
+			var err error
+			current, err = io.ReadAll(body)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	body, err := c.dryRun.add(req)
+	if err != nil || current == nil {
+		return body, err
+	}
+	if activation {
+		body = []byte(`{"state":"active"}`)
+	}
+	return mergeObjects(current, body), nil
+}
+
+// mergeObjects returns the JSON object current with the members of patch replacing
+// its own. If either is not an object, patch is returned unchanged.
+func mergeObjects(current, patch []byte) []byte {
+	var fields, changes map[string]json.RawMessage
+	if json.Unmarshal(current, &fields) != nil || fields == nil || json.Unmarshal(patch, &changes) != nil {
+		return patch
+	}
+	for key, value := range changes {
+		fields[key] = value
+	}
+	merged, err := json.Marshal(fields)
+	if err != nil {
+		return patch
+	}
+	return merged
+}
+
+// WithDryRun records POST, PUT, PATCH and DELETE calls, and domain activation, in plan
+// instead of sending them. Other GET calls still reach the API, and so do the reads
+// that build the results of updates, as described below, so read-modify-write code
+// sees the resource it changed.
+//
+// The result of a planned call is synthesized: an update returns the current resource
+// with the request body applied, ActivateDomain returns the domain with an active
+// state, a create returns what its request sets, and a delete returns nothing.
+// Server-side effects, such as computed fields of a new resource, are not reflected.
+func WithDryRun(plan *Plan) Option {
+	return func(c *Client) {
+		c.dryRun = plan
+	}
+}
@@ -0,0 +1,87 @@
+package migadu
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDryRunPlansMutationsAndSendsReads(t *testing.T) {
+	var sent []string
+	plan := NewPlan()
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithDryRun(plan),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			sent = append(sent, req.Method+" "+req.URL.Path)
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"local_part":"demo","name":"Old"}`))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	mailbox, err := client.GetMailbox(ctx, "example.com", "demo")
+	if err != nil || mailbox.Name != "Old" {
+		t.Fatalf("GetMailbox() = %+v, %v", mailbox, err)
+	}
+	name := "New"
+	updated, err := client.UpdateMailbox(ctx, "example.com", "demo", UpdateMailboxRequest{Name: &name})
+	if err != nil || updated.Name != "New" || updated.LocalPart != "demo" {
+		t.Fatalf("UpdateMailbox() = %+v, %v", updated, err)
+	}
+	if err = client.DeleteAlias(ctx, "example.com", "info"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The update reads the mailbox again to build its result; the delete sends nothing.
+	if len(sent) != 2 || sent[1] != "GET /v1/domains/example.com/mailboxes/demo" {
+		t.Fatalf("sent = %q", sent)
+	}
+	requests := plan.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("plan = %+v", requests)
+	}
+	if requests[0].Method != http.MethodPut || requests[0].Path != "/v1/domains/example.com/mailboxes/demo" || string(requests[0].Body) != `{"name":"New"}` {
+		t.Fatalf("planned update = %+v (%s)", requests[0], requests[0].Body)
+	}
+	if requests[1].Method != http.MethodDelete || requests[1].Path != "/v1/domains/example.com/aliases/info" || requests[1].Body != nil {
+		t.Fatalf("planned delete = %+v", requests[1])
+	}
+}
+
+func TestDryRunPlansActivation(t *testing.T) {
+	var sent []string
+	plan := NewPlan()
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithDryRun(plan),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			sent = append(sent, req.Method+" "+req.URL.Path)
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"name":"example.com","state":"pending"}`))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	domain, err := client.ActivateDomain(context.Background(), "example.com")
+	if err != nil || domain.Name != "example.com" || domain.State != DomainStateActive {
+		t.Fatalf("ActivateDomain() = %+v, %v", domain, err)
+	}
+	if len(sent) != 1 || sent[0] != "GET /v1/domains/example.com" {
+		t.Fatalf("sent = %q", sent)
+	}
+	var out map[string]any
+	if err = client.Do(context.Background(), http.MethodGet, []string{"domains", "example.com", "activate"}, nil, &out); err != nil || out["state"] != "active" {
+		t.Fatalf("Do() activate = %v, %v", out, err)
+	}
+	if len(sent) != 2 || sent[1] != "GET /v1/domains/example.com" {
+		t.Fatalf("sent = %q", sent)
+	}
+	requests := plan.Requests()
+	if len(requests) != 2 || requests[1].Method != http.MethodGet || requests[1].Path != "/v1/domains/example.com/activate" {
+		t.Fatalf("plan = %+v", requests)
+	}
+}
@@ -0,0 +1,182 @@
+package migadu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidValue is wrapped by the errors of enumerated values that are not known.
+var ErrInvalidValue = errors.New("invalid value")
+
+// DomainState is the lifecycle state of a domain. Unknown states returned by the
+// API are kept as they are.
+type DomainState string
+
+const (
+	DomainStateActive    DomainState = "active"
+	DomainStatePending   DomainState = "pending"
+	DomainStateInactive  DomainState = "inactive"
+	DomainStateSuspended DomainState = "suspended"
+)
+
+var domainStates = []DomainState{DomainStateActive, DomainStatePending, DomainStateInactive, DomainStateSuspended}
+
+// ParseDomainState parses a known state, ignoring case and surrounding space.
+func ParseDomainState(value string) (DomainState, error) {
+	return parseEnum("domain state", value, domainStates)
+}
+
+// Validate reports whether s is empty or a known state.
+func (s DomainState) Validate() error {
+	return validateEnum("domain state", s, domainStates)
+}
+
+// SpamAction is what happens to a message classified as spam.
+type SpamAction string
+
+const (
+	SpamActionFolder SpamAction = "folder"
+	SpamActionTag    SpamAction = "tag"
+	SpamActionDrop   SpamAction = "drop"
+)
+
+var spamActions = []SpamAction{SpamActionFolder, SpamActionTag, SpamActionDrop}
+
+// ParseSpamAction parses a known spam action, ignoring case and surrounding space.
+func ParseSpamAction(value string) (SpamAction, error) {
+	return parseEnum("spam action", value, spamActions)
+}
+
+// Validate reports whether a is empty or a known spam action.
+func (a SpamAction) Validate() error {
+	return validateEnum("spam action", a, spamActions)
+}
+
+// SpamAggressiveness is how eagerly messages are classified as spam.
+type SpamAggressiveness string
+
+const (
+	SpamAggressivenessMostPermissive SpamAggressiveness = "most_permissive"
+	SpamAggressivenessMorePermissive SpamAggressiveness = "more_permissive"
+	SpamAggressivenessPermissive     SpamAggressiveness = "permissive"
+	SpamAggressivenessDefault        SpamAggressiveness = "default"
+	SpamAggressivenessStrict         SpamAggressiveness = "strict"
+	SpamAggressivenessStricter       SpamAggressiveness = "stricter"
+	SpamAggressivenessStrictest      SpamAggressiveness = "strictest"
+)
+
+// spamAggressivenessLevels are ordered from -3 to 3, the numeric representation the API also uses.
+var spamAggressivenessLevels = []SpamAggressiveness{
+	SpamAggressivenessMostPermissive,
+	SpamAggressivenessMorePermissive,
+	SpamAggressivenessPermissive,
+	SpamAggressivenessDefault,
+	SpamAggressivenessStrict,
+	SpamAggressivenessStricter,
+	SpamAggressivenessStrictest,
+}
+
+// ParseSpamAggressiveness parses a known level by name or by its number from -3 to 3.
+func ParseSpamAggressiveness(value string) (SpamAggressiveness, error) {
+	return parseEnum("spam aggressiveness", string(normalizeSpamAggressiveness(value)), spamAggressivenessLevels)
+}
+
+// Validate reports whether a is empty or a known level.
+func (a SpamAggressiveness) Validate() error {
+	return validateEnum("spam aggressiveness", a, spamAggressivenessLevels)
+}
+
+// UnmarshalJSON accepts names and the numeric levels from -3 to 3, as strings or numbers,
+// and normalizes numbers to names. Unknown values are kept as they are.
+func (a *SpamAggressiveness) UnmarshalJSON(data []byte) error {
+	var value stringValue
+	if err := value.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*a = normalizeSpamAggressiveness(string(value))
+	return nil
+}
+
+func normalizeSpamAggressiveness(value string) SpamAggressiveness {
+	value = strings.TrimSpace(value)
+	if n, err := json.Number(value).Int64(); err == nil && n >= -3 && n <= 3 {
+		return spamAggressivenessLevels[n+3]
+	}
+	return SpamAggressiveness(value)
+}
+
+// PasswordMethod is how a new mailbox gets its password.
+type PasswordMethod string
+
+const (
+	// PasswordMethodPassword sets the password given in the request.
+	PasswordMethodPassword PasswordMethod = "password"
+	// PasswordMethodInvitation emails an invitation to the recovery address.
+	PasswordMethodInvitation PasswordMethod = "invitation"
+)
+
+var passwordMethods = []PasswordMethod{PasswordMethodPassword, PasswordMethodInvitation}
+
+// ParsePasswordMethod parses a known password method, ignoring case and surrounding space.
+func ParsePasswordMethod(value string) (PasswordMethod, error) {
+	return parseEnum("password method", value, passwordMethods)
+}
+
+// Validate reports whether m is empty or a known password method.
+func (m PasswordMethod) Validate() error {
+	return validateEnum("password method", m, passwordMethods)
+}
+
+func parseEnum[T ~string](kind, value string, known []T) (T, error) {
+	value = strings.TrimSpace(value)
+	for _, candidate := range known {
+		if strings.EqualFold(value, string(candidate)) {
+			return candidate, nil
+		}
+	}
+	return T(value), invalidEnum(kind, value, known)
+}
+
+func validateEnum[T ~string](kind string, value T, known []T) error {
+	if value == "" {
+		return nil
+	}
+	for _, candidate := range known {
+		if value == candidate {
+			return nil
+		}
+	}
+	return invalidEnum(kind, string(value), known)
+}
+
+func invalidEnum[T ~string](kind, value string, known []T) error {
+	names := make([]string, len(known))
+	for i, candidate := range known {
+		names[i] = string(candidate)
+	}
+	return &enumError{kind: kind, value: value, known: names}
+}
+
+// enumError reports a value outside known. Validate uses known to describe the field.
+type enumError struct {
+	kind  string
+	value string
+	known []string
+}
+
+func (e *enumError) Error() string {
+	return fmt.Sprintf("%s: %s %q, want one of %s", ErrInvalidValue, e.kind, e.value, strings.Join(e.known, ", "))
+}
+
+func (e *enumError) Unwrap() error { return ErrInvalidValue }
+
+// deref returns the value p points to, or the zero value for nil.
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
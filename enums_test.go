@@ -0,0 +1,114 @@
+package migadu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseEnums(t *testing.T) {
+	if state, err := ParseDomainState(" Active "); err != nil || state != DomainStateActive {
+		t.Fatalf("ParseDomainState() = %q, %v", state, err)
+	}
+	if action, err := ParseSpamAction("TAG"); err != nil || action != SpamActionTag {
+		t.Fatalf("ParseSpamAction() = %q, %v", action, err)
+	}
+	if level, err := ParseSpamAggressiveness("-3"); err != nil || level != SpamAggressivenessMostPermissive {
+		t.Fatalf("ParseSpamAggressiveness() = %q, %v", level, err)
+	}
+	if method, err := ParsePasswordMethod("Invitation"); err != nil || method != PasswordMethodInvitation {
+		t.Fatalf("ParsePasswordMethod() = %q, %v", method, err)
+	}
+	_, err := ParseSpamAction("quarantine")
+	if !errors.Is(err, ErrInvalidValue) || !strings.Contains(err.Error(), `"quarantine", want one of folder, tag, drop`) {
+		t.Fatalf("ParseSpamAction() error = %v", err)
+	}
+}
+
+func TestValidateEnums(t *testing.T) {
+	for _, value := range []interface{ Validate() error }{
+		DomainState(""), DomainStateSuspended, SpamActionDrop, SpamAggressivenessStrictest, PasswordMethodPassword,
+	} {
+		if err := value.Validate(); err != nil {
+			t.Fatalf("%q.Validate() = %v", value, err)
+		}
+	}
+	for _, value := range []interface{ Validate() error }{
+		DomainState("gone"), SpamAction("Folder"), SpamAggressiveness("4"), PasswordMethod("magic"),
+	} {
+		if err := value.Validate(); !errors.Is(err, ErrInvalidValue) {
+			t.Fatalf("%q.Validate() = %v", value, err)
+		}
+	}
+}
+
+func TestDecodingKeepsUnknownEnumValues(t *testing.T) {
+	var mailbox Mailbox
+	body := `{"local_part":"demo","spam_action":"quarantine","spam_aggressiveness":"-1","password_method":"sso"}`
+	if err := json.Unmarshal([]byte(body), &mailbox); err != nil {
+		t.Fatal(err)
+	}
+	if mailbox.SpamAction != "quarantine" || mailbox.PasswordMethod != "sso" {
+		t.Fatalf("Mailbox = %+v", mailbox)
+	}
+	if mailbox.SpamAggressiveness != SpamAggressivenessPermissive {
+		t.Fatalf("SpamAggressiveness = %q", mailbox.SpamAggressiveness)
+	}
+
+	var domain Domain
+	if err := json.Unmarshal([]byte(`{"name":"example.com","state":"archived","spam_aggressiveness":7}`), &domain); err != nil {
+		t.Fatal(err)
+	}
+	if domain.State != "archived" || domain.SpamAggressiveness != "7" {
+		t.Fatalf("Domain = %+v", domain)
+	}
+}
+
+func TestInvalidEnumRequestsAreNotSent(t *testing.T) {
+	sent := 0
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			sent++
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	action := SpamAction("quarantine")
+	level := SpamAggressiveness("extreme")
+	for name, call := range map[string]func() error{
+		"CreateDomain": func() error {
+			_, err := client.CreateDomain(ctx, CreateDomainRequest{Name: "example.com", SpamAggressiveness: level})
+			return err
+		},
+		"UpdateDomain": func() error {
+			_, err := client.UpdateDomain(ctx, "example.com", UpdateDomainRequest{SpamAggressiveness: &level})
+			return err
+		},
+		"CreateMailbox": func() error {
+			_, err := client.CreateMailbox(ctx, "example.com", CreateMailboxRequest{LocalPart: "demo", PasswordMethod: "magic"})
+			return err
+		},
+		"UpdateMailbox": func() error {
+			_, err := client.UpdateMailbox(ctx, "example.com", "demo", UpdateMailboxRequest{SpamAction: &action})
+			return err
+		},
+	} {
+		if err := call(); !errors.Is(err, ErrInvalidValue) {
+			t.Fatalf("%s() error = %v", name, err)
+		}
+	}
+	if sent != 0 {
+		t.Fatalf("sent %d requests", sent)
+	}
+	if _, err = client.UpdateMailbox(ctx, "example.com", "demo", UpdateMailboxRequest{}); err != nil || sent != 1 {
+		t.Fatalf("UpdateMailbox() = %v, sent %d", err, sent)
+	}
+}
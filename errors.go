@@ -0,0 +1,173 @@
+package migadu
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Sentinel errors matched by *APIError through errors.Is.
+var (
+	ErrNotFound     = errors.New("resource not found")
+	ErrConflict     = errors.New("resource conflict")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+	ErrServer       = errors.New("server error")
+)
+
+// FieldError describes a problem with a single request field reported by the API.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) String() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + " " + e.Message
+}
+
+// Is reports whether the status code of e falls into the class of target.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict || e.isTakenValidation()
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// isTakenValidation detects Migadu reporting a duplicate local part as a validation error.
+func (e *APIError) isTakenValidation() bool {
+	if e.StatusCode != http.StatusUnprocessableEntity {
+		return false
+	}
+	for _, fieldErr := range e.FieldErrors {
+		if strings.Contains(strings.ToLower(fieldErr.Message), "already been taken") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound reports whether err is an API error for a missing resource.
+func IsNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
+
+// IsConflict reports whether err is an API error for a resource that already exists.
+func IsConflict(err error) bool { return errors.Is(err, ErrConflict) }
+
+// IsUnauthorized reports whether err is an API error for rejected credentials.
+func IsUnauthorized(err error) bool { return errors.Is(err, ErrUnauthorized) }
+
+// IsForbidden reports whether err is an API error for a disallowed operation.
+func IsForbidden(err error) bool { return errors.Is(err, ErrForbidden) }
+
+// IsRateLimited reports whether err is an API error for a throttled request.
+func IsRateLimited(err error) bool { return errors.Is(err, ErrRateLimited) }
+
+// IsValidation reports whether err is an API error for an invalid request.
+func IsValidation(err error) bool { return errors.Is(err, ErrValidation) }
+
+// IsServer reports whether err is an API error caused by Migadu itself.
+func IsServer(err error) bool { return errors.Is(err, ErrServer) }
+
+// parseAPIError decodes the error body shapes Migadu has been seen to return:
+// {"error":"code","message":"text"}, {"error":{"message":"text"}}, {"message":"text"},
+// {"errors":{"field":["text"]}}, {"errors":[{"field":"f","message":"text"}]},
+// {"errors":["text"]} and plain text.
+func parseAPIError(status int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: status, Body: string(body)}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return apiErr
+	}
+	if raw, ok := fields["error"]; ok {
+		var code string
+		if err := json.Unmarshal(raw, &code); err == nil {
+			apiErr.Code = code
+		} else {
+			var nested struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			}
+			if err = json.Unmarshal(raw, &nested); err == nil {
+				apiErr.Code = nested.Code
+				apiErr.Message = nested.Message
+			}
+		}
+	}
+	if raw, ok := fields["message"]; ok {
+		_ = json.Unmarshal(raw, &apiErr.Message)
+	}
+	if raw, ok := fields["errors"]; ok {
+		apiErr.FieldErrors = parseFieldErrors(raw)
+	}
+	return apiErr
+}
+
+func parseFieldErrors(raw json.RawMessage) []FieldError {
+	var byField map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &byField); err == nil {
+		names := make([]string, 0, len(byField))
+		for name := range byField {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var result []FieldError
+		for _, name := range names {
+			var messages []string
+			if err := json.Unmarshal(byField[name], &messages); err != nil {
+				var message string
+				if err = json.Unmarshal(byField[name], &message); err != nil {
+					message = string(byField[name])
+				}
+				messages = []string{message}
+			}
+			for _, message := range messages {
+				result = append(result, FieldError{Field: name, Message: message})
+			}
+		}
+		return result
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil
+	}
+	var result []FieldError
+	for _, item := range items {
+		var message string
+		if err := json.Unmarshal(item, &message); err == nil {
+			result = append(result, FieldError{Message: message})
+			continue
+		}
+		var fieldErr FieldError
+		if err := json.Unmarshal(item, &fieldErr); err == nil {
+			result = append(result, fieldErr)
+		}
+	}
+	return result
+}
+
+func formatFieldErrors(fieldErrors []FieldError) string {
+	parts := make([]string, len(fieldErrors))
+	for i, fieldErr := range fieldErrors {
+		parts[i] = fieldErr.String()
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,73 @@
+package migadu
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAPIErrorMatchesSentinels(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   error
+	}{
+		{status: http.StatusNotFound, want: ErrNotFound},
+		{status: http.StatusConflict, want: ErrConflict},
+		{status: http.StatusUnprocessableEntity, body: `{"errors":{"local_part":["has already been taken"]}}`, want: ErrConflict},
+		{status: http.StatusUnauthorized, want: ErrUnauthorized},
+		{status: http.StatusForbidden, want: ErrForbidden},
+		{status: http.StatusTooManyRequests, want: ErrRateLimited},
+		{status: http.StatusBadRequest, want: ErrValidation},
+		{status: http.StatusUnprocessableEntity, want: ErrValidation},
+		{status: http.StatusBadGateway, want: ErrServer},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("wrapped: %w", parseAPIError(tt.status, []byte(tt.body)))
+		if !errors.Is(err, tt.want) {
+			t.Errorf("status %d: errors.Is(%v) = false", tt.status, tt.want)
+		}
+	}
+	if IsNotFound(parseAPIError(http.StatusUnprocessableEntity, nil)) || IsServer(parseAPIError(http.StatusNotFound, nil)) {
+		t.Fatal("predicate matched an unrelated status")
+	}
+}
+
+func TestParseAPIErrorBodyShapes(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		code        string
+		message     string
+		fieldErrors []FieldError
+	}{
+		{name: "code and message", body: `{"error":"dns_check_failed","message":"DNS checks failed"}`, code: "dns_check_failed", message: "DNS checks failed"},
+		{name: "nested error", body: `{"error":{"code":"not_found","message":"Mailbox not found"}}`, code: "not_found", message: "Mailbox not found"},
+		{name: "field map", body: `{"errors":{"local_part":["is invalid","is too long"],"name":"can't be blank"}}`, fieldErrors: []FieldError{
+			{Field: "local_part", Message: "is invalid"}, {Field: "local_part", Message: "is too long"}, {Field: "name", Message: "can't be blank"},
+		}},
+		{name: "field list", body: `{"errors":[{"field":"address","message":"is invalid"}]}`, fieldErrors: []FieldError{{Field: "address", Message: "is invalid"}}},
+		{name: "message list", body: `{"errors":["Something went wrong"]}`, fieldErrors: []FieldError{{Message: "Something went wrong"}}},
+		{name: "plain text", body: `Bad Gateway`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := parseAPIError(http.StatusUnprocessableEntity, []byte(tt.body))
+			if apiErr.Code != tt.code || apiErr.Message != tt.message || !reflect.DeepEqual(apiErr.FieldErrors, tt.fieldErrors) {
+				t.Fatalf("APIError = %+v", apiErr)
+			}
+			if apiErr.Body != tt.body {
+				t.Fatalf("Body = %q", apiErr.Body)
+			}
+		})
+	}
+}
+
+func TestAPIErrorMessageIncludesFieldErrors(t *testing.T) {
+	err := parseAPIError(http.StatusUnprocessableEntity, []byte(`{"errors":{"local_part":["is invalid"]}}`))
+	if got, want := err.Error(), "Migadu API returned status 422: local_part is invalid"; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+}
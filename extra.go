@@ -0,0 +1,84 @@
+package migadu
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// knownFieldsCache maps a struct type to the lower-cased JSON names of its fields.
+var knownFieldsCache sync.Map
+
+func knownFields(t reflect.Type) map[string]struct{} {
+	if cached, ok := knownFieldsCache.Load(t); ok {
+		return cached.(map[string]struct{})
+	}
+	known := map[string]struct{}{}
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			known[strings.ToLower(name)] = struct{}{}
+		}
+	}
+	knownFieldsCache.Store(t, known)
+	return known
+}
+
+// extraFields returns the members of the JSON object data that v, a pointer
+// to a struct, has no field for. Names match case-insensitively, as in encoding/json.
+func extraFields(data []byte, v any) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	known := knownFields(reflect.TypeOf(v).Elem())
+	for key := range fields {
+		if _, ok := known[strings.ToLower(key)]; ok {
+			delete(fields, key)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// unmarshalWithExtra decodes data into v, a pointer to a struct, and returns the
+// members v has no field for. v is usually a method-less copy of the type being
+// decoded, declared locally as "type plain T" to avoid recursing into UnmarshalJSON.
+func unmarshalWithExtra(data []byte, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	return extraFields(data, v)
+}
+
+// marshalWithExtra encodes v, a pointer to a struct, and appends the extra
+// members that do not collide with its fields.
+func marshalWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	known := knownFields(reflect.TypeOf(v).Elem())
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		if _, ok := known[strings.ToLower(key)]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	out := data[:len(data)-1]
+	for _, key := range keys {
+		if len(out) > 1 {
+			out = append(out, ',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		out = append(append(append(out, name...), ':'), extra[key]...)
+	}
+	return append(out, '}'), nil
+}
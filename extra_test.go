@@ -0,0 +1,37 @@
+package migadu
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtraKeepsUnknownFields(t *testing.T) {
+	data := `{"local_part":"demo","Name":"Demo","quota_warning":80,"labels":{"team":"ops"}}`
+	var mailbox Mailbox
+	if err := json.Unmarshal([]byte(data), &mailbox); err != nil {
+		t.Fatal(err)
+	}
+	if mailbox.Name != "Demo" || len(mailbox.Extra) != 2 || string(mailbox.Extra["quota_warning"]) != "80" {
+		t.Fatalf("mailbox = %+v", mailbox)
+	}
+	encoded, err := json.Marshal(mailbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"local_part":"demo","name":"Demo","labels":{"team":"ops"},"quota_warning":80}`; string(encoded) != want {
+		t.Fatalf("Marshal() = %s, want %s", encoded, want)
+	}
+
+	var domain Domain
+	if err = json.Unmarshal([]byte(`{"name":"example.com","tags":"a, b","plan":"micro"}`), &domain); err != nil {
+		t.Fatal(err)
+	}
+	if len(domain.Tags) != 2 || len(domain.Extra) != 1 || string(domain.Extra["plan"]) != `"micro"` {
+		t.Fatalf("domain = %+v", domain)
+	}
+
+	var alias Alias
+	if err = json.Unmarshal([]byte(`{"local_part":"info"}`), &alias); err != nil || alias.Extra != nil {
+		t.Fatalf("alias = %+v, %v", alias, err)
+	}
+}
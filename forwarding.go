@@ -2,33 +2,67 @@ package migadu
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 )
 
 // Forwarding represents an external forwarding address for a mailbox.
 type Forwarding struct {
-	Address            string  `json:"address,omitempty"`
-	BlockedAt          *string `json:"blocked_at,omitempty"`
-	ConfirmationSentAt *string `json:"confirmation_sent_at,omitempty"`
-	ConfirmedAt        *string `json:"confirmed_at,omitempty"`
-	ExpiresOn          *string `json:"expires_on,omitempty"`
-	IsActive           bool    `json:"is_active,omitempty"`
-	RemoveUponExpiry   *bool   `json:"remove_upon_expiry,omitempty"`
+	Address            string     `json:"address,omitempty"`
+	BlockedAt          *Timestamp `json:"blocked_at,omitempty"`
+	ConfirmationSentAt *Timestamp `json:"confirmation_sent_at,omitempty"`
+	ConfirmedAt        *Timestamp `json:"confirmed_at,omitempty"`
+	ExpiresOn          *Date      `json:"expires_on,omitempty"`
+	IsActive           bool       `json:"is_active,omitempty"`
+	RemoveUponExpiry   *bool      `json:"remove_upon_expiry,omitempty"`
+	// Extra holds JSON fields this version of the library does not model.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON keeps unknown fields in Extra.
+func (f *Forwarding) UnmarshalJSON(data []byte) error {
+	type plain Forwarding
+	extra, err := unmarshalWithExtra(data, (*plain)(f))
+	f.Extra = extra
+	return err
+}
+
+// MarshalJSON writes Extra back next to the known fields.
+func (f Forwarding) MarshalJSON() ([]byte, error) {
+	type plain Forwarding
+	return marshalWithExtra((*plain)(&f), f.Extra)
 }
 
 // CreateForwardingRequest contains fields accepted by the forwarding create endpoint.
 type CreateForwardingRequest struct {
-	Address          string  `json:"address"`
-	ExpiresOn        *string `json:"expires_on,omitempty"`
-	IsActive         *bool   `json:"is_active,omitempty"`
-	RemoveUponExpiry *bool   `json:"remove_upon_expiry,omitempty"`
+	Address          string `json:"address"`
+	ExpiresOn        *Date  `json:"expires_on,omitempty"`
+	IsActive         *bool  `json:"is_active,omitempty"`
+	RemoveUponExpiry *bool  `json:"remove_upon_expiry,omitempty"`
 }
 
 // UpdateForwardingRequest uses pointers so zero values can be sent explicitly.
 type UpdateForwardingRequest struct {
-	ExpiresOn        *string `json:"expires_on,omitempty"`
-	IsActive         *bool   `json:"is_active,omitempty"`
-	RemoveUponExpiry *bool   `json:"remove_upon_expiry,omitempty"`
+	ExpiresOn        *Date `json:"expires_on,omitempty"`
+	IsActive         *bool `json:"is_active,omitempty"`
+	RemoveUponExpiry *bool `json:"remove_upon_expiry,omitempty"`
+}
+
+// Validate checks the request locally. It returns a *ValidationError listing every invalid field.
+func (r CreateForwardingRequest) Validate() error {
+	var v validator
+	if v.required("address", r.Address) {
+		v.address("address", r.Address)
+	}
+	v.date("expires_on", r.ExpiresOn)
+	return v.err()
+}
+
+// Validate checks the fields that are set. It returns a *ValidationError listing every invalid field.
+func (r UpdateForwardingRequest) Validate() error {
+	var v validator
+	v.date("expires_on", r.ExpiresOn)
+	return v.err()
 }
 
 // ListForwardings lists all external forwarding addresses on a mailbox.
@@ -45,13 +79,11 @@ func (c *Client) ListForwardings(ctx context.Context, domain, mailbox string) ([
 	if err != nil {
 		return nil, err
 	}
-	resp, err := doRequest[struct {
-		Forwardings []*Forwarding `json:"forwardings"`
-	}](c, ctx, req)
+	items, err := doListRequest[Forwarding](c, ctx, req, "forwardings")
 	if err != nil {
 		return nil, err
 	}
-	return resp.Forwardings, nil
+	return items, nil
 }
 
 // GetForwarding retrieves an external forwarding address on a mailbox.
@@ -73,6 +105,9 @@ func (c *Client) GetForwarding(ctx context.Context, domain, mailbox, address str
 
 // CreateForwarding adds an external forwarding address to a mailbox.
 func (c *Client) CreateForwarding(ctx context.Context, domain, mailbox string, forwarding CreateForwardingRequest) (*Forwarding, error) {
+	if err := c.validate(forwarding); err != nil {
+		return nil, err
+	}
 	builder, err := c.getDomainReqBuilder(domain)
 	if err != nil {
 		return nil, err
@@ -92,6 +127,9 @@ func (c *Client) CreateForwarding(ctx context.Context, domain, mailbox string, f
 
 // UpdateForwarding updates an external forwarding address on a mailbox.
 func (c *Client) UpdateForwarding(ctx context.Context, domain, mailbox, address string, update UpdateForwardingRequest) (*Forwarding, error) {
+	if err := c.validate(update); err != nil {
+		return nil, err
+	}
 	builder, err := c.getDomainReqBuilder(domain)
 	if err != nil {
 		return nil, err
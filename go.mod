@@ -1,3 +1,3 @@
 module github.com/z-xavier/migadu-go
 
-go 1.18
+go 1.24
@@ -15,7 +15,7 @@ type httpReqBuilder struct {
 	host      string
 	path      string
 	header    *http.Header
-	body      io.Reader
+	body      []byte
 	basicAuth *basicAuth
 	err       error
 }
@@ -60,6 +60,14 @@ func (b *httpReqBuilder) SetHeader(key, value string) *httpReqBuilder {
 	return b
 }
 
+func (b *httpReqBuilder) AddHeader(key, value string) *httpReqBuilder {
+	if b.header == nil {
+		b.header = &http.Header{}
+	}
+	b.header.Add(key, value)
+	return b
+}
+
 func (b *httpReqBuilder) SetBasicAuth(username, password string) *httpReqBuilder {
 	b.basicAuth = &basicAuth{username, password}
 	return b
@@ -71,7 +79,7 @@ func (b *httpReqBuilder) SetBodyJson(body interface{}) *httpReqBuilder {
 		b.err = fmt.Errorf("encode JSON request body: %w", err)
 		return b
 	}
-	b.body = bytes.NewBuffer(jsonStr)
+	b.body = jsonStr
 	return b
 }
 
@@ -89,7 +97,12 @@ func (b *httpReqBuilder) Build() (*http.Request, error) {
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(b.method, parse.String(), b.body)
+	var body io.Reader
+	if b.body != nil {
+		// A bytes.Reader lets http.NewRequest set GetBody so the request can be replayed on retry.
+		body = bytes.NewReader(b.body)
+	}
+	req, err := http.NewRequest(b.method, parse.String(), body)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
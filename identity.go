@@ -2,6 +2,7 @@ package migadu
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 )
 
@@ -20,6 +21,22 @@ type Identity struct {
 	MaySend              bool   `json:"may_send,omitempty"`
 	Name                 string `json:"name,omitempty"`
 	Password             string `json:"password,omitempty"`
+	// Extra holds JSON fields this version of the library does not model.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON keeps unknown fields in Extra.
+func (i *Identity) UnmarshalJSON(data []byte) error {
+	type plain Identity
+	extra, err := unmarshalWithExtra(data, (*plain)(i))
+	i.Extra = extra
+	return err
+}
+
+// MarshalJSON writes Extra back next to the known fields.
+func (i Identity) MarshalJSON() ([]byte, error) {
+	type plain Identity
+	return marshalWithExtra((*plain)(&i), i.Extra)
 }
 
 // CreateIdentityRequest contains fields accepted by the identity create endpoint.
@@ -51,6 +68,20 @@ type UpdateIdentityRequest struct {
 	FooterHTMLBody       *string `json:"footer_html_body,omitempty"`
 }
 
+// Validate checks the request locally. It returns a *ValidationError listing every invalid field.
+func (r CreateIdentityRequest) Validate() error {
+	var v validator
+	v.localPart("local_part", r.LocalPart)
+	return v.err()
+}
+
+// Validate checks the fields that are set. It returns a *ValidationError listing every invalid field.
+func (r UpdateIdentityRequest) Validate() error {
+	var v validator
+	v.optional("password", r.Password)
+	return v.err()
+}
+
 // ListIdentities lists all the identities for the given mailbox local part name.
 // It returns the identities and any error encountered.
 func (c *Client) ListIdentities(ctx context.Context, domain, mailbox string) ([]*Identity, error) {
@@ -67,13 +98,11 @@ func (c *Client) ListIdentities(ctx context.Context, domain, mailbox string) ([]
 		return nil, err
 	}
 
-	resp, err := doRequest[struct {
-		Identities []*Identity `json:"identities,omitempty"`
-	}](c, ctx, req)
+	items, err := doListRequest[Identity](c, ctx, req, "identities")
 	if err != nil {
 		return nil, err
 	}
-	return resp.Identities, nil
+	return items, nil
 }
 
 // GetIdentity  retrieves a single identity given its mailbox name and local part name.
@@ -97,6 +126,9 @@ func (c *Client) GetIdentity(ctx context.Context, domain, mailbox, localPart str
 
 // CreateIdentity creates an identity using all fields supported by the API.
 func (c *Client) CreateIdentity(ctx context.Context, domain, mailbox string, identity CreateIdentityRequest) (*Identity, error) {
+	if err := c.validate(identity); err != nil {
+		return nil, err
+	}
 	builder, err := c.getDomainReqBuilder(domain)
 	if err != nil {
 		return nil, err
@@ -116,6 +148,9 @@ func (c *Client) CreateIdentity(ctx context.Context, domain, mailbox string, ide
 
 // UpdateIdentity updates only fields explicitly set on update.
 func (c *Client) UpdateIdentity(ctx context.Context, domain, mailbox, localPart string, update UpdateIdentityRequest) (*Identity, error) {
+	if err := c.validate(update); err != nil {
+		return nil, err
+	}
 	builder, err := c.getDomainReqBuilder(domain)
 	if err != nil {
 		return nil, err
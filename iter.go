@@ -0,0 +1,173 @@
+package migadu
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// IterOption configures an account-wide iterator such as AllMailboxes.
+type IterOption func(*iterConfig)
+
+type iterConfig struct {
+	concurrency int
+}
+
+// WithConcurrency lets an account-wide iterator list up to n domains at once.
+// Results are still yielded in domain order, and at most n pages, including the
+// one being yielded, are held at a time, so a slow loop body also slows the
+// requests down. Values below 2 list one domain at a time.
+func WithConcurrency(n int) IterOption {
+	return func(cfg *iterConfig) {
+		cfg.concurrency = n
+	}
+}
+
+// Domains returns an iterator over the domains visible to the authenticated account.
+func (c *Client) Domains(ctx context.Context) iter.Seq2[*Domain, error] {
+	return seq(func() ([]*Domain, error) { return c.ListDomains(ctx) })
+}
+
+// Mailboxes returns an iterator over the mailboxes of a domain.
+func (c *Client) Mailboxes(ctx context.Context, domain string) iter.Seq2[*Mailbox, error] {
+	return seq(func() ([]*Mailbox, error) { return c.ListMailboxes(ctx, domain) })
+}
+
+// Aliases returns an iterator over the aliases of a domain.
+func (c *Client) Aliases(ctx context.Context, domain string) iter.Seq2[*Alias, error] {
+	return seq(func() ([]*Alias, error) { return c.ListAliases(ctx, domain) })
+}
+
+// Rewrites returns an iterator over the rewrites of a domain.
+func (c *Client) Rewrites(ctx context.Context, domain string) iter.Seq2[*Rewrite, error] {
+	return seq(func() ([]*Rewrite, error) { return c.ListRewrites(ctx, domain) })
+}
+
+// Identities returns an iterator over the identities of a mailbox.
+func (c *Client) Identities(ctx context.Context, domain, mailbox string) iter.Seq2[*Identity, error] {
+	return seq(func() ([]*Identity, error) { return c.ListIdentities(ctx, domain, mailbox) })
+}
+
+// Forwardings returns an iterator over the forwardings of a mailbox.
+func (c *Client) Forwardings(ctx context.Context, domain, mailbox string) iter.Seq2[*Forwarding, error] {
+	return seq(func() ([]*Forwarding, error) { return c.ListForwardings(ctx, domain, mailbox) })
+}
+
+// AllMailboxes returns an iterator over the mailboxes of every domain. Domains
+// are listed lazily, and breaking out of the loop stops any further requests.
+// A failure to list one domain is yielded as an error wrapping the domain name,
+// and iteration continues with the next domain.
+func (c *Client) AllMailboxes(ctx context.Context, opts ...IterOption) iter.Seq2[*Mailbox, error] {
+	return walkDomains(c, ctx, c.ListMailboxes, opts)
+}
+
+// AllAliases returns an iterator over the aliases of every domain. It behaves like AllMailboxes.
+func (c *Client) AllAliases(ctx context.Context, opts ...IterOption) iter.Seq2[*Alias, error] {
+	return walkDomains(c, ctx, c.ListAliases, opts)
+}
+
+// AllRewrites returns an iterator over the rewrites of every domain. It behaves like AllMailboxes.
+func (c *Client) AllRewrites(ctx context.Context, opts ...IterOption) iter.Seq2[*Rewrite, error] {
+	return walkDomains(c, ctx, c.ListRewrites, opts)
+}
+
+func seq[T any](list func() ([]*T, error)) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		items, err := list()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+type domainPage[T any] struct {
+	items []*T
+	err   error
+}
+
+func walkDomains[T any](c *Client, ctx context.Context, list func(context.Context, string) ([]*T, error), opts []IterOption) iter.Seq2[*T, error] {
+	var cfg iterConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(yield func(*T, error) bool) {
+		domains, err := c.ListDomains(ctx)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		defer cancel()
+
+		pages := make([]chan domainPage[T], len(domains))
+		for i := range pages {
+			pages[i] = make(chan domainPage[T], 1)
+		}
+		fetch := func(i int) {
+			items, err := list(ctx, domains[i].Name)
+			if err != nil {
+				err = fmt.Errorf("%s: %w", domains[i].Name, err)
+			}
+			pages[i] <- domainPage[T]{items: items, err: err}
+		}
+		// A slot is taken for each fetch and only given back once its page has been yielded.
+		sem := make(chan struct{}, cfg.concurrency)
+		if cfg.concurrency > 1 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range domains {
+					select {
+					case sem <- struct{}{}:
+					case <-ctx.Done():
+						return
+					}
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						fetch(i)
+					}()
+				}
+			}()
+		}
+
+		for i := range domains {
+			var page domainPage[T]
+			if cfg.concurrency <= 1 {
+				fetch(i)
+				page = <-pages[i]
+			} else {
+				// The producer stops launching fetches once ctx is done, so later pages may never arrive.
+				select {
+				case page = <-pages[i]:
+				case <-ctx.Done():
+					yield(nil, ctx.Err())
+					return
+				}
+			}
+			if page.err != nil {
+				if !yield(nil, page.err) {
+					return
+				}
+			} else {
+				for _, item := range page.items {
+					if !yield(item, nil) {
+						return
+					}
+				}
+			}
+			if cfg.concurrency > 1 {
+				<-sem
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package migadu
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newIterTestClient(t *testing.T, requests *atomic.Int32) *Client {
+	t.Helper()
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			requests.Add(1)
+			body := `{"domains":[{"name":"a.example"},{"name":"broken.example"},{"name":"c.example"},{"name":"d.example"}]}`
+			status := http.StatusOK
+			if domain, ok := strings.CutPrefix(req.URL.Path, "/v1/domains/"); ok {
+				domain, _, _ = strings.Cut(domain, "/")
+				body = fmt.Sprintf(`{"mailboxes":[{"local_part":"one","domain_name":%q},{"local_part":"two","domain_name":%q}]}`, domain, domain)
+				if domain == "broken.example" {
+					status, body = http.StatusInternalServerError, `{"error":"boom"}`
+				}
+			}
+			return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}, nil
+		})),
+		WithRetryPolicy(nil),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestAllMailboxesYieldsInDomainOrder(t *testing.T) {
+	for _, concurrency := range []int{0, 3} {
+		var requests atomic.Int32
+		client := newIterTestClient(t, &requests)
+		var got []string
+		var errs []error
+		for mailbox, err := range client.AllMailboxes(context.Background(), WithConcurrency(concurrency)) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			got = append(got, mailbox.LocalPart+"@"+mailbox.DomainName)
+		}
+		want := "one@a.example two@a.example one@c.example two@c.example one@d.example two@d.example"
+		if strings.Join(got, " ") != want {
+			t.Fatalf("concurrency %d: mailboxes = %q", concurrency, got)
+		}
+		if len(errs) != 1 || !IsServer(errs[0]) || !strings.Contains(errs[0].Error(), "broken.example") {
+			t.Fatalf("concurrency %d: errors = %v", concurrency, errs)
+		}
+	}
+}
+
+func TestAllMailboxesStopsEarly(t *testing.T) {
+	var requests atomic.Int32
+	client := newIterTestClient(t, &requests)
+	for range client.AllMailboxes(context.Background()) {
+		break
+	}
+	if n := requests.Load(); n != 2 {
+		t.Fatalf("requests = %d, want 2", n)
+	}
+
+	// Breaking out of a concurrent walk must cancel the fetches in flight and return.
+	for range client.AllMailboxes(context.Background(), WithConcurrency(2)) {
+		break
+	}
+}
+
+func TestMailboxesIterator(t *testing.T) {
+	var requests atomic.Int32
+	client := newIterTestClient(t, &requests)
+	count := 0
+	for mailbox, err := range client.Mailboxes(context.Background(), "a.example") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mailbox.DomainName != "a.example" {
+			t.Fatalf("mailbox = %+v", mailbox)
+		}
+		count++
+	}
+	if count != 2 {
+		t.Fatalf("count = %d", count)
+	}
+}
+
+func TestConcurrentWalkWaitsForTheConsumer(t *testing.T) {
+	var requests atomic.Int32
+	client := newIterTestClient(t, &requests)
+	seen := 0
+	for _, err := range client.AllMailboxes(context.Background(), WithConcurrency(2)) {
+		if err != nil {
+			continue
+		}
+		if seen++; seen == 1 {
+			// While the first page is being consumed, only the next one may be fetched.
+			time.Sleep(20 * time.Millisecond)
+			if n := requests.Load(); n != 3 {
+				t.Fatalf("requests while paused = %d, want 3", n)
+			}
+		}
+	}
+	if n := requests.Load(); n != 5 || seen != 6 {
+		t.Fatalf("requests = %d, mailboxes = %d", n, seen)
+	}
+}
@@ -0,0 +1,209 @@
+package migadu
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// maxLoggedBodyBytes caps the part of a response body that is logged. Only that
+// part is read ahead; the rest keeps streaming to the decoder and its size limit.
+const maxLoggedBodyBytes = 64 << 10
+
+// loggingMiddleware logs every attempt through logger. Bodies are only read and
+// logged when the logger is enabled at debug level, and secrets are always redacted.
+func loggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next HTTPDoer) HTTPDoer {
+		return HTTPDoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			debug := logger.Enabled(ctx, slog.LevelDebug)
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+			}
+			if debug {
+				attrs = append(attrs, slog.Any("request_header", redactHeader(req.Header)))
+				if body := requestBody(req); len(body) > 0 {
+					attrs = append(attrs, slog.String("request_body", string(redactBody(body))))
+				}
+			}
+			start := time.Now()
+			resp, err := next.Do(req)
+			attrs = append(attrs, slog.Duration("latency", time.Since(start)))
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				logger.LogAttrs(ctx, slog.LevelError, "migadu request failed", attrs...)
+				return nil, err
+			}
+			attrs = append(attrs, slog.Int("status", resp.StatusCode))
+			if debug {
+				head, readErr := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBodyBytes+1))
+				var rest io.Reader = resp.Body
+				if readErr != nil {
+					rest = errReader{readErr}
+				}
+				resp.Body = replayBody{Reader: io.MultiReader(bytes.NewReader(head), rest), Closer: resp.Body}
+				if len(head) > maxLoggedBodyBytes {
+					attrs = append(attrs,
+						slog.String("response_body", string(redactPartialBody(head[:maxLoggedBodyBytes]))),
+						slog.Bool("response_body_truncated", true))
+				} else if len(head) > 0 {
+					attrs = append(attrs, slog.String("response_body", string(redactBody(head))))
+				}
+			}
+			level := slog.LevelInfo
+			if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+				level = slog.LevelWarn
+			}
+			logger.LogAttrs(ctx, level, "migadu request", attrs...)
+			return resp, nil
+		})
+	}
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// replayBody reads the logged head of a response body and then the rest of it.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+	data, _ := io.ReadAll(body)
+	return data
+}
+
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, key := range []string{"Authorization", "Proxy-Authorization", "Cookie"} {
+		if header.Get(key) != "" {
+			header.Set(key, redacted)
+		}
+	}
+	return header
+}
+
+// redactBody replaces every "password" value in a JSON body. Bodies that are
+// not JSON are returned unchanged because the API only accepts JSON.
+func redactBody(body []byte) []byte {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+	redactValue(value)
+	redactedBody, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return redactedBody
+}
+
+// partialPasswordPattern matches a "password" member of JSON that may be cut off, including its value.
+var partialPasswordPattern = regexp.MustCompile(`(?i)("password"\s*:\s*)"(?:[^"\\]|\\.)*"?`)
+
+// redactPartialBody redacts a body cut off at the logging cap, which no longer parses as JSON.
+func redactPartialBody(body []byte) []byte {
+	return partialPasswordPattern.ReplaceAll(body, []byte(`${1}"`+redacted+`"`))
+}
+
+func redactValue(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if strings.EqualFold(key, "password") {
+				v[key] = redacted
+				continue
+			}
+			redactValue(item)
+		}
+	case []any:
+		for _, item := range v {
+			redactValue(item)
+		}
+	}
+}
+
+func redactString(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
+
+func redactStringPtr(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	result := redacted
+	return &result
+}
+
+// LogValue implements slog.LogValuer so mailbox passwords never reach logs.
+func (m Mailbox) LogValue() slog.Value {
+	type mailbox Mailbox
+	m.Password = redactString(m.Password)
+	if m.Identities != nil {
+		identities := make([]Identity, len(m.Identities))
+		for i, identity := range m.Identities {
+			identity.Password = redactString(identity.Password)
+			identities[i] = identity
+		}
+		m.Identities = identities
+	}
+	return slog.AnyValue(mailbox(m))
+}
+
+// LogValue implements slog.LogValuer so identity passwords never reach logs.
+func (i Identity) LogValue() slog.Value {
+	type identity Identity
+	i.Password = redactString(i.Password)
+	return slog.AnyValue(identity(i))
+}
+
+// LogValue implements slog.LogValuer so the password is never logged.
+func (r CreateMailboxRequest) LogValue() slog.Value {
+	type request CreateMailboxRequest
+	r.Password = redactString(r.Password)
+	return slog.AnyValue(request(r))
+}
+
+// LogValue implements slog.LogValuer so the password is never logged.
+func (r UpdateMailboxRequest) LogValue() slog.Value {
+	type request UpdateMailboxRequest
+	r.Password = redactStringPtr(r.Password)
+	return slog.AnyValue(request(r))
+}
+
+// LogValue implements slog.LogValuer so the password is never logged.
+func (r CreateIdentityRequest) LogValue() slog.Value {
+	type request CreateIdentityRequest
+	r.Password = redactString(r.Password)
+	return slog.AnyValue(request(r))
+}
+
+// LogValue implements slog.LogValuer so the password is never logged.
+func (r UpdateIdentityRequest) LogValue() slog.Value {
+	type request UpdateIdentityRequest
+	r.Password = redactStringPtr(r.Password)
+	return slog.AnyValue(request(r))
+}
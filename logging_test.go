@@ -0,0 +1,121 @@
+package migadu
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLoggerRedactsCredentialsAndPasswords(t *testing.T) {
+	var out bytes.Buffer
+	client, err := New("admin@example.com", "api-key-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.baseURL = "https://api.test"
+	client.logger = slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client.httpClient = doerFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		if !strings.Contains(string(body), "hunter2") {
+			t.Errorf("request body sent = %s", body)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"address":"demo@example.com","password":"hunter2"}`)),
+		}, nil
+	})
+	mailbox, err := client.CreateMailbox(context.Background(), "example.com", CreateMailboxRequest{LocalPart: "demo", Name: "Demo", Password: "hunter2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mailbox.Password != "hunter2" {
+		t.Fatalf("logging consumed the response body: %+v", mailbox)
+	}
+	logged := out.String()
+	for _, secret := range []string{"hunter2", "api-key-secret", "YWRtaW5AZXhhbXBsZS5jb206YXBpLWtleS1zZWNyZXQ"} {
+		if strings.Contains(logged, secret) {
+			t.Fatalf("log leaked %q: %s", secret, logged)
+		}
+	}
+	for _, want := range []string{`"method":"POST"`, `"path":"/v1/domains/example.com/mailboxes"`, `"status":200`, `"latency"`, `"request_body"`, `"response_body"`} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("log missing %s: %s", want, logged)
+		}
+	}
+}
+
+func TestLoggerSkipsBodiesAboveDebug(t *testing.T) {
+	var out bytes.Buffer
+	client := &Client{
+		baseURL: "https://api.test",
+		logger:  slog.New(slog.NewTextHandler(&out, nil)),
+		httpClient: doerFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		}),
+	}
+	_, _ = client.GetMailbox(context.Background(), "example.com", "demo")
+	logged := out.String()
+	if !strings.Contains(logged, "level=WARN") || !strings.Contains(logged, "status=404") || strings.Contains(logged, "response_body") {
+		t.Fatalf("log = %s", logged)
+	}
+}
+
+func TestLogValuersRedactPasswords(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, nil))
+	password := "hunter2"
+	logger.Info("values",
+		"mailbox", Mailbox{Address: "demo@example.com", Password: password, Identities: []Identity{{Password: password}}},
+		"identity", Identity{Password: password},
+		"create_mailbox", CreateMailboxRequest{Password: password},
+		"update_mailbox", UpdateMailboxRequest{Password: &password},
+		"create_identity", CreateIdentityRequest{Password: password},
+		"update_identity", UpdateIdentityRequest{Password: &password},
+	)
+	if logged := out.String(); strings.Contains(logged, password) || !strings.Contains(logged, "demo@example.com") {
+		t.Fatalf("log = %s", logged)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestDebugLoggingKeepsResponseStreaming(t *testing.T) {
+	var out bytes.Buffer
+	entries := strings.Repeat(`{"local_part":"demo","password":"hunter2"},`, 8000)
+	body := &countingReader{r: strings.NewReader(`{"mailboxes":[` + entries + `{}]}`)}
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithLogger(slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		WithMaxResponseBytes(2*maxLoggedBodyBytes),
+		WithHTTPClient(doerFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body)}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.ListMailboxes(context.Background(), "example.com"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("ListMailboxes() error = %v", err)
+	}
+	if total := len(entries); body.n >= total {
+		t.Fatalf("read %d of %d bytes", body.n, total)
+	}
+	logged := out.String()
+	if strings.Contains(logged, "hunter2") || !strings.Contains(logged, `"response_body_truncated":true`) {
+		t.Fatalf("log = %.300s", logged)
+	}
+}
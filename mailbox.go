@@ -2,99 +2,149 @@ package migadu
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 )
 
 // Mailbox represents a mailbox in the Migadu API.
 type Mailbox struct {
-	Address               string     `json:"address,omitempty"`
-	AutorespondActive     bool       `json:"autorespond_active,omitempty"`
-	AutorespondBody       string     `json:"autorespond_body,omitempty"`
-	AutorespondExpiresOn  string     `json:"autorespond_expires_on,omitempty"`
-	AutorespondSubject    string     `json:"autorespond_subject,omitempty"`
-	ChangedAt             string     `json:"changed_at,omitempty"`
-	Delegations           []string   `json:"delegations,omitempty"`
-	DomainName            string     `json:"domain_name,omitempty"`
-	Expireable            bool       `json:"expireable,omitempty"`
-	ExpiresOn             string     `json:"expires_on,omitempty"`
-	FooterActive          bool       `json:"footer_active,omitempty"`
-	FooterHTMLBody        string     `json:"footer_html_body,omitempty"`
-	FooterPlainBody       string     `json:"footer_plain_body,omitempty"`
-	Identities            []Identity `json:"identities,omitempty"`
-	IsInternal            bool       `json:"is_internal,omitempty"`
-	LastLoginAt           string     `json:"last_login_at,omitempty"`
-	LocalPart             string     `json:"local_part,omitempty"`
-	MayAccessImap         bool       `json:"may_access_imap,omitempty"`
-	MayAccessManagesieve  bool       `json:"may_access_managesieve,omitempty"`
-	MayAccessPop3         bool       `json:"may_access_pop3,omitempty"`
-	MayReceive            bool       `json:"may_receive,omitempty"`
-	MaySend               bool       `json:"may_send,omitempty"`
-	Name                  string     `json:"name,omitempty"`
-	Password              string     `json:"password,omitempty"`
-	PasswordMethod        string     `json:"password_method,omitempty"`
-	PasswordRecoveryEmail string     `json:"password_recovery_email,omitempty"`
-	RecipientDenylist     []string   `json:"recipient_denylist,omitempty"`
-	RemoveUponExpiry      bool       `json:"remove_upon_expiry,omitempty"`
-	SenderAllowlist       []string   `json:"sender_allowlist,omitempty"`
-	SenderDenylist        []string   `json:"sender_denylist,omitempty"`
-	SpamAction            string     `json:"spam_action,omitempty"`
-	SpamAggressiveness    string     `json:"spam_aggressiveness,omitempty"`
-	StorageUsage          float64    `json:"storage_usage,omitempty"`
-	WildcardSender        bool       `json:"wildcard_sender,omitempty"`
+	Address               string             `json:"address,omitempty"`
+	AutorespondActive     bool               `json:"autorespond_active,omitempty"`
+	AutorespondBody       string             `json:"autorespond_body,omitempty"`
+	AutorespondExpiresOn  Date               `json:"autorespond_expires_on,omitzero"`
+	AutorespondSubject    string             `json:"autorespond_subject,omitempty"`
+	ChangedAt             Timestamp          `json:"changed_at,omitzero"`
+	Delegations           []string           `json:"delegations,omitempty"`
+	DomainName            string             `json:"domain_name,omitempty"`
+	Expireable            bool               `json:"expireable,omitempty"`
+	ExpiresOn             Date               `json:"expires_on,omitzero"`
+	FooterActive          bool               `json:"footer_active,omitempty"`
+	FooterHTMLBody        string             `json:"footer_html_body,omitempty"`
+	FooterPlainBody       string             `json:"footer_plain_body,omitempty"`
+	Identities            []Identity         `json:"identities,omitempty"`
+	IsInternal            bool               `json:"is_internal,omitempty"`
+	LastLoginAt           Timestamp          `json:"last_login_at,omitzero"`
+	LocalPart             string             `json:"local_part,omitempty"`
+	MayAccessImap         bool               `json:"may_access_imap,omitempty"`
+	MayAccessManagesieve  bool               `json:"may_access_managesieve,omitempty"`
+	MayAccessPop3         bool               `json:"may_access_pop3,omitempty"`
+	MayReceive            bool               `json:"may_receive,omitempty"`
+	MaySend               bool               `json:"may_send,omitempty"`
+	Name                  string             `json:"name,omitempty"`
+	Password              string             `json:"password,omitempty"`
+	PasswordMethod        PasswordMethod     `json:"password_method,omitempty"`
+	PasswordRecoveryEmail string             `json:"password_recovery_email,omitempty"`
+	RecipientDenylist     []string           `json:"recipient_denylist,omitempty"`
+	RemoveUponExpiry      bool               `json:"remove_upon_expiry,omitempty"`
+	SenderAllowlist       []string           `json:"sender_allowlist,omitempty"`
+	SenderDenylist        []string           `json:"sender_denylist,omitempty"`
+	SpamAction            SpamAction         `json:"spam_action,omitempty"`
+	SpamAggressiveness    SpamAggressiveness `json:"spam_aggressiveness,omitempty"`
+	StorageUsage          float64            `json:"storage_usage,omitempty"`
+	WildcardSender        bool               `json:"wildcard_sender,omitempty"`
+	// Extra holds JSON fields this version of the library does not model.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON keeps unknown fields in Extra.
+func (m *Mailbox) UnmarshalJSON(data []byte) error {
+	type plain Mailbox
+	extra, err := unmarshalWithExtra(data, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+// MarshalJSON writes Extra back next to the known fields.
+func (m Mailbox) MarshalJSON() ([]byte, error) {
+	type plain Mailbox
+	return marshalWithExtra((*plain)(&m), m.Extra)
 }
 
 // CreateMailboxRequest contains fields accepted by the mailbox create endpoint.
 type CreateMailboxRequest struct {
-	LocalPart             string   `json:"local_part"`
-	Name                  string   `json:"name,omitempty"`
-	PasswordMethod        string   `json:"password_method,omitempty"`
-	Password              string   `json:"password,omitempty"`
-	PasswordRecoveryEmail string   `json:"password_recovery_email,omitempty"`
-	ForwardingTo          string   `json:"forwarding_to,omitempty"`
-	IsInternal            *bool    `json:"is_internal,omitempty"`
-	WildcardSender        *bool    `json:"wildcard_sender,omitempty"`
-	MaySend               *bool    `json:"may_send,omitempty"`
-	MayReceive            *bool    `json:"may_receive,omitempty"`
-	MayAccessImap         *bool    `json:"may_access_imap,omitempty"`
-	MayAccessPop3         *bool    `json:"may_access_pop3,omitempty"`
-	MayAccessManagesieve  *bool    `json:"may_access_managesieve,omitempty"`
-	SpamAction            string   `json:"spam_action,omitempty"`
-	SpamAggressiveness    string   `json:"spam_aggressiveness,omitempty"`
-	SenderDenylist        []string `json:"sender_denylist,omitempty"`
-	SenderAllowlist       []string `json:"sender_allowlist,omitempty"`
-	RecipientDenylist     []string `json:"recipient_denylist,omitempty"`
-	FooterActive          *bool    `json:"footer_active,omitempty"`
-	FooterPlainBody       *string  `json:"footer_plain_body,omitempty"`
-	FooterHTMLBody        *string  `json:"footer_html_body,omitempty"`
+	LocalPart             string             `json:"local_part"`
+	Name                  string             `json:"name,omitempty"`
+	PasswordMethod        PasswordMethod     `json:"password_method,omitempty"`
+	Password              string             `json:"password,omitempty"`
+	PasswordRecoveryEmail string             `json:"password_recovery_email,omitempty"`
+	ForwardingTo          string             `json:"forwarding_to,omitempty"`
+	IsInternal            *bool              `json:"is_internal,omitempty"`
+	WildcardSender        *bool              `json:"wildcard_sender,omitempty"`
+	MaySend               *bool              `json:"may_send,omitempty"`
+	MayReceive            *bool              `json:"may_receive,omitempty"`
+	MayAccessImap         *bool              `json:"may_access_imap,omitempty"`
+	MayAccessPop3         *bool              `json:"may_access_pop3,omitempty"`
+	MayAccessManagesieve  *bool              `json:"may_access_managesieve,omitempty"`
+	SpamAction            SpamAction         `json:"spam_action,omitempty"`
+	SpamAggressiveness    SpamAggressiveness `json:"spam_aggressiveness,omitempty"`
+	SenderDenylist        []string           `json:"sender_denylist,omitempty"`
+	SenderAllowlist       []string           `json:"sender_allowlist,omitempty"`
+	RecipientDenylist     []string           `json:"recipient_denylist,omitempty"`
+	FooterActive          *bool              `json:"footer_active,omitempty"`
+	FooterPlainBody       *string            `json:"footer_plain_body,omitempty"`
+	FooterHTMLBody        *string            `json:"footer_html_body,omitempty"`
 }
 
 // UpdateMailboxRequest uses pointers so zero values can be sent explicitly.
 type UpdateMailboxRequest struct {
-	Name                  *string   `json:"name,omitempty"`
-	IsInternal            *bool     `json:"is_internal,omitempty"`
-	WildcardSender        *bool     `json:"wildcard_sender,omitempty"`
-	MaySend               *bool     `json:"may_send,omitempty"`
-	MayReceive            *bool     `json:"may_receive,omitempty"`
-	MayAccessImap         *bool     `json:"may_access_imap,omitempty"`
-	MayAccessPop3         *bool     `json:"may_access_pop3,omitempty"`
-	MayAccessManagesieve  *bool     `json:"may_access_managesieve,omitempty"`
-	Password              *string   `json:"password,omitempty"`
-	PasswordRecoveryEmail *string   `json:"password_recovery_email,omitempty"`
-	AutorespondActive     *bool     `json:"autorespond_active,omitempty"`
-	AutorespondBody       *string   `json:"autorespond_body,omitempty"`
-	AutorespondExpiresOn  *string   `json:"autorespond_expires_on,omitempty"`
-	AutorespondSubject    *string   `json:"autorespond_subject,omitempty"`
-	Delegations           *[]string `json:"delegations,omitempty"`
-	ExpiresOn             *string   `json:"expires_on,omitempty"`
-	RemoveUponExpiry      *bool     `json:"remove_upon_expiry,omitempty"`
-	RecipientDenylist     *[]string `json:"recipient_denylist,omitempty"`
-	SenderAllowlist       *[]string `json:"sender_allowlist,omitempty"`
-	SenderDenylist        *[]string `json:"sender_denylist,omitempty"`
-	SpamAction            *string   `json:"spam_action,omitempty"`
-	SpamAggressiveness    *string   `json:"spam_aggressiveness,omitempty"`
-	FooterActive          *bool     `json:"footer_active,omitempty"`
-	FooterPlainBody       *string   `json:"footer_plain_body,omitempty"`
-	FooterHTMLBody        *string   `json:"footer_html_body,omitempty"`
+	Name                  *string             `json:"name,omitempty"`
+	IsInternal            *bool               `json:"is_internal,omitempty"`
+	WildcardSender        *bool               `json:"wildcard_sender,omitempty"`
+	MaySend               *bool               `json:"may_send,omitempty"`
+	MayReceive            *bool               `json:"may_receive,omitempty"`
+	MayAccessImap         *bool               `json:"may_access_imap,omitempty"`
+	MayAccessPop3         *bool               `json:"may_access_pop3,omitempty"`
+	MayAccessManagesieve  *bool               `json:"may_access_managesieve,omitempty"`
+	Password              *string             `json:"password,omitempty"`
+	PasswordRecoveryEmail *string             `json:"password_recovery_email,omitempty"`
+	AutorespondActive     *bool               `json:"autorespond_active,omitempty"`
+	AutorespondBody       *string             `json:"autorespond_body,omitempty"`
+	AutorespondExpiresOn  *Date               `json:"autorespond_expires_on,omitempty"`
+	AutorespondSubject    *string             `json:"autorespond_subject,omitempty"`
+	Delegations           *[]string           `json:"delegations,omitempty"`
+	ExpiresOn             *Date               `json:"expires_on,omitempty"`
+	RemoveUponExpiry      *bool               `json:"remove_upon_expiry,omitempty"`
+	RecipientDenylist     *[]string           `json:"recipient_denylist,omitempty"`
+	SenderAllowlist       *[]string           `json:"sender_allowlist,omitempty"`
+	SenderDenylist        *[]string           `json:"sender_denylist,omitempty"`
+	SpamAction            *SpamAction         `json:"spam_action,omitempty"`
+	SpamAggressiveness    *SpamAggressiveness `json:"spam_aggressiveness,omitempty"`
+	FooterActive          *bool               `json:"footer_active,omitempty"`
+	FooterPlainBody       *string             `json:"footer_plain_body,omitempty"`
+	FooterHTMLBody        *string             `json:"footer_html_body,omitempty"`
+}
+
+// Validate checks the request locally. It returns a *ValidationError listing every invalid field.
+// An invitation needs a PasswordRecoveryEmail; otherwise a Password is required.
+func (r CreateMailboxRequest) Validate() error {
+	var v validator
+	v.localPart("local_part", r.LocalPart)
+	v.required("name", r.Name)
+	v.enum("password_method", r.PasswordMethod)
+	switch r.PasswordMethod {
+	case PasswordMethodInvitation:
+		v.required("password_recovery_email", r.PasswordRecoveryEmail)
+	case "", PasswordMethodPassword:
+		v.required("password", r.Password)
+	}
+	v.address("password_recovery_email", r.PasswordRecoveryEmail)
+	v.address("forwarding_to", r.ForwardingTo)
+	v.enum("spam_action", r.SpamAction)
+	v.enum("spam_aggressiveness", r.SpamAggressiveness)
+	return v.err()
+}
+
+// Validate checks the fields that are set. It returns a *ValidationError listing every invalid field.
+func (r UpdateMailboxRequest) Validate() error {
+	var v validator
+	v.optional("name", r.Name)
+	v.optional("password", r.Password)
+	v.address("password_recovery_email", deref(r.PasswordRecoveryEmail))
+	v.date("autorespond_expires_on", r.AutorespondExpiresOn)
+	v.date("expires_on", r.ExpiresOn)
+	v.enum("spam_action", deref(r.SpamAction))
+	v.enum("spam_aggressiveness", deref(r.SpamAggressiveness))
+	return v.err()
 }
 
 // ListMailboxes lists all mailboxes for a domain.
@@ -112,13 +162,11 @@ func (c *Client) ListMailboxes(ctx context.Context, domain string) ([]*Mailbox,
 		return nil, err
 	}
 
-	resp, err := doRequest[struct {
-		Mailboxes []*Mailbox `json:"mailboxes,omitempty"`
-	}](c, ctx, req)
+	items, err := doListRequest[Mailbox](c, ctx, req, "mailboxes")
 	if err != nil {
 		return nil, err
 	}
-	return resp.Mailboxes, nil
+	return items, nil
 }
 
 // GetMailbox retrieves a single mailbox given its local part name.
@@ -140,6 +188,9 @@ func (c *Client) GetMailbox(ctx context.Context, domain, localPart string) (*Mai
 
 // CreateMailbox creates a mailbox using all fields supported by the API.
 func (c *Client) CreateMailbox(ctx context.Context, domain string, mailbox CreateMailboxRequest) (*Mailbox, error) {
+	if err := c.validate(mailbox); err != nil {
+		return nil, err
+	}
 	builder, err := c.getDomainReqBuilder(domain)
 	if err != nil {
 		return nil, err
@@ -158,6 +209,9 @@ func (c *Client) CreateMailbox(ctx context.Context, domain string, mailbox Creat
 
 // UpdateMailbox updates only fields explicitly set on update.
 func (c *Client) UpdateMailbox(ctx context.Context, domain, localPart string, update UpdateMailboxRequest) (*Mailbox, error) {
+	if err := c.validate(update); err != nil {
+		return nil, err
+	}
 	builder, err := c.getDomainReqBuilder(domain)
 	if err != nil {
 		return nil, err
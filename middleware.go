@@ -0,0 +1,86 @@
+package migadu
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPDoerFunc adapts an ordinary function to the HTTPDoer interface.
+type HTTPDoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f HTTPDoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps an HTTPDoer to observe or modify every HTTP attempt made by a Client.
+// Retries and rate limiting happen outside the chain, so middlewares see each attempt.
+type Middleware func(next HTTPDoer) HTTPDoer
+
+// Chain wraps doer with middlewares. The first middleware is the outermost one
+// and sees the request first.
+func Chain(doer HTTPDoer, middlewares ...Middleware) HTTPDoer {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		if middlewares[i] != nil {
+			doer = middlewares[i](doer)
+		}
+	}
+	return doer
+}
+
+// UserAgentMiddleware sets the User-Agent header on every request.
+func UserAgentMiddleware(userAgent string) Middleware {
+	return HeaderMiddleware(http.Header{"User-Agent": []string{userAgent}})
+}
+
+// HeaderMiddleware sets headers on every request, replacing existing values with the same key.
+func HeaderMiddleware(header http.Header) Middleware {
+	header = header.Clone()
+	return func(next HTTPDoer) HTTPDoer {
+		return HTTPDoerFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			if req.Header == nil {
+				req.Header = http.Header{}
+			}
+			for key, values := range header {
+				req.Header.Del(key)
+				for _, value := range values {
+					req.Header.Add(key, value)
+				}
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// TimeoutMiddleware bounds each attempt by timeout, including reading the response body.
+// Unlike WithTimeout, which covers all retries of a call, it lets a hung attempt be retried.
+func TimeoutMiddleware(timeout time.Duration) Middleware {
+	return func(next HTTPDoer) HTTPDoer {
+		return HTTPDoerFunc(func(req *http.Request) (*http.Response, error) {
+			if timeout <= 0 {
+				return next.Do(req)
+			}
+			ctx, cancel := context.WithTimeout(req.Context(), timeout)
+			resp, err := next.Do(req.WithContext(ctx))
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		})
+	}
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,85 @@
+package migadu
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMiddlewareRunsInOrder(t *testing.T) {
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next HTTPDoer) HTTPDoer {
+			return HTTPDoerFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+" before")
+				resp, err := next.Do(req)
+				order = append(order, name+" after")
+				return resp, err
+			})
+		}
+	}
+	var userAgent string
+	client := &Client{
+		baseURL: "https://api.test",
+		httpClient: doerFunc(func(req *http.Request) (*http.Response, error) {
+			order = append(order, "transport")
+			userAgent = req.Header.Get("User-Agent")
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		}),
+		middleware: []Middleware{trace("outer"), UserAgentMiddleware("provisioner/1.0"), trace("inner")},
+	}
+	if _, err := client.GetDomain(context.Background(), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"outer before", "inner before", "transport", "inner after", "outer after"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("order = %q, want %q", order, want)
+	}
+	if userAgent != "provisioner/1.0" {
+		t.Fatalf("User-Agent = %q", userAgent)
+	}
+}
+
+func TestTimeoutMiddlewareBoundsEachAttempt(t *testing.T) {
+	attempts := 0
+	client := &Client{
+		baseURL: "https://api.test",
+		retry:   &RetryPolicy{MaxAttempts: 2},
+		httpClient: doerFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			if attempts == 1 {
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"name":"example.com"}`))}, nil
+		}),
+		middleware: []Middleware{TimeoutMiddleware(time.Millisecond)},
+	}
+	domain, err := client.GetDomain(context.Background(), "example.com")
+	if err != nil || domain.Name != "example.com" || attempts != 2 {
+		t.Fatalf("domain = %+v, error = %v, attempts = %d", domain, err, attempts)
+	}
+}
+
+func TestTimeoutMiddlewareKeepsBodyReadable(t *testing.T) {
+	doer := Chain(doerFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+	}), TimeoutMiddleware(time.Second))
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	resp, err := doer.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != "ok" {
+		t.Fatalf("body = %q, error = %v", body, err)
+	}
+	if err = resp.Body.Close(); err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,234 @@
+package migadutest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	migadu "github.com/z-xavier/migadu-go"
+)
+
+// ErrUnmatchedRequest is returned in replay mode when no recorded interaction
+// matches a request, which usually means the API usage drifted from the recording.
+var ErrUnmatchedRequest = errors.New("migadutest: no recorded interaction matches request")
+
+const scrubbed = "[SCRUBBED]"
+
+// scrubbedHeaders are never written to a cassette.
+var scrubbedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request used for matching.
+type RecordedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// RecordedResponse is a replayable response.
+type RecordedResponse struct {
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	// Text holds a body that is not JSON.
+	Text string `json:"text,omitempty"`
+}
+
+// Cassette is an HTTPDoer that records interactions with a real transport to a
+// JSON file, or replays them without network access. Requests match on method,
+// path and normalized JSON body; credentials and passwords are scrubbed before
+// anything is written.
+type Cassette struct {
+	path      string
+	recording bool
+	next      migadu.HTTPDoer
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecordingCassette returns a cassette that forwards requests to next and
+// records them. Call Save to write the file.
+func NewRecordingCassette(path string, next migadu.HTTPDoer) *Cassette {
+	if next == nil {
+		next = http.DefaultClient
+	}
+	return &Cassette{path: path, recording: true, next: next}
+}
+
+// LoadCassette reads a cassette file for replay.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+	var file struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode cassette %s: %w", path, err)
+	}
+	// Cassettes are indented on disk and may be edited by hand, so normalize again before matching.
+	for i := range file.Interactions {
+		file.Interactions[i].Request.Body = normalizeBody(file.Interactions[i].Request.Body)
+	}
+	return &Cassette{path: path, interactions: file.Interactions, used: make([]bool, len(file.Interactions))}, nil
+}
+
+// Do records or replays req.
+func (c *Cassette) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded := RecordedRequest{Method: req.Method, Path: req.URL.Path, Body: normalizeBody(body)}
+	if c.recording {
+		return c.record(req, recorded)
+	}
+	return c.replay(req, recorded)
+}
+
+func (c *Cassette) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	resp, err := c.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	recordedResp := RecordedResponse{Status: resp.StatusCode, Header: resp.Header.Clone()}
+	for _, key := range scrubbedHeaders {
+		recordedResp.Header.Del(key)
+	}
+	// The body is re-encoded when normalized, so its original length no longer applies.
+	recordedResp.Header.Del("Content-Length")
+	if normalized := normalizeBody(body); normalized != nil {
+		recordedResp.Body = normalized
+	} else {
+		recordedResp.Text = string(body)
+	}
+	c.mu.Lock()
+	c.interactions = append(c.interactions, Interaction{Request: recorded, Response: recordedResp})
+	c.used = append(c.used, true)
+	c.mu.Unlock()
+	return resp, nil
+}
+
+func (c *Cassette) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, interaction := range c.interactions {
+		if c.used[i] || !interaction.Request.matches(recorded) {
+			continue
+		}
+		c.used[i] = true
+		body := []byte(interaction.Response.Text)
+		if interaction.Response.Body != nil {
+			body = interaction.Response.Body
+		}
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s %s", ErrUnmatchedRequest, recorded.Method, recorded.Path, recorded.Body)
+}
+
+func (r RecordedRequest) matches(other RecordedRequest) bool {
+	return r.Method == other.Method && r.Path == other.Path && bytes.Equal(r.Body, other.Body)
+}
+
+// Unused returns the recorded interactions that have not been replayed yet.
+func (c *Cassette) Unused() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range c.interactions {
+		if !c.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	file := struct {
+		Interactions []Interaction `json:"interactions"`
+	}{Interactions: c.interactions}
+	data, err := json.MarshalIndent(file, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o644)
+}
+
+// normalizeBody re-encodes a JSON body with sorted keys and scrubbed passwords.
+// It returns nil for empty or non-JSON bodies.
+func normalizeBody(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil
+	}
+	value = scrubValue(value)
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return normalized
+}
+
+func scrubValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if strings.EqualFold(key, "password") {
+				v[key] = scrubbed
+				continue
+			}
+			v[key] = scrubValue(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = scrubValue(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,74 @@
+package migadutest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	migadu "github.com/z-xavier/migadu-go"
+)
+
+func TestCassetteRecordsAndReplays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	s := NewUnstartedServer()
+	s.AddDomain(migadu.Domain{Name: "example.com"})
+	recorder := NewRecordingCassette(path, s.Doer())
+	client, err := migadu.NewWithOptions(DefaultEmail, DefaultAPIKey, migadu.WithBaseURL("https://api.migadu.test"), migadu.WithHTTPClient(recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	request := migadu.CreateMailboxRequest{LocalPart: "demo", Name: "Demo", Password: "hunter2"}
+	if _, err = client.CreateMailbox(ctx, "example.com", request); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetMailbox(ctx, "example.com", "demo"); err != nil {
+		t.Fatal(err)
+	}
+	if err = recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"hunter2", DefaultAPIKey, "Authorization"} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("cassette leaked %q:\n%s", secret, data)
+		}
+	}
+
+	player, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayClient, err := migadu.NewWithOptions(DefaultEmail, "other-key", migadu.WithBaseURL("https://api.migadu.test"), migadu.WithHTTPClient(player))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Password = "different"
+	if _, err = replayClient.CreateMailbox(ctx, "example.com", request); err != nil {
+		t.Fatalf("replayed CreateMailbox() error = %v", err)
+	}
+	mailbox, err := replayClient.GetMailbox(ctx, "example.com", "demo")
+	if err != nil || mailbox.Name != "Demo" {
+		t.Fatalf("replayed GetMailbox() = %+v, %v", mailbox, err)
+	}
+	if unused := player.Unused(); len(unused) != 0 {
+		t.Fatalf("Unused() = %+v", unused)
+	}
+	if _, err = replayClient.GetMailbox(ctx, "example.com", "demo"); !errors.Is(err, ErrUnmatchedRequest) {
+		t.Fatalf("unrecorded request error = %v", err)
+	}
+}
+
+func TestCassetteMatchesNormalizedJSON(t *testing.T) {
+	a := normalizeBody([]byte(`{"b": 1, "a": [true, {"password": "x"}]}`))
+	b := normalizeBody([]byte(`{"a":[true,{"password":"y"}],"b":1}`))
+	if string(a) != string(b) {
+		t.Fatalf("normalized bodies differ: %s vs %s", a, b)
+	}
+}
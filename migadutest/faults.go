@@ -0,0 +1,144 @@
+package migadutest
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"time"
+)
+
+// Matcher selects the requests a Fault applies to.
+type Matcher func(r *http.Request) bool
+
+// MatchMethod matches every request with the given HTTP method.
+func MatchMethod(method string) Matcher {
+	return func(r *http.Request) bool {
+		return r.Method == method
+	}
+}
+
+// MatchRoute matches requests with the given method whose path matches pattern
+// as in path.Match, so "/v1/domains/*/mailboxes" matches CreateMailbox on any domain.
+// An empty method matches any method.
+func MatchRoute(method, pattern string) Matcher {
+	return func(r *http.Request) bool {
+		if method != "" && r.Method != method {
+			return false
+		}
+		matched, err := path.Match(pattern, r.URL.Path)
+		return err == nil && matched
+	}
+}
+
+// Fault scripts a misbehaviour of the server. Faults are evaluated in the order
+// they were added; delays of every applicable fault add up, and the first
+// applicable fault with a Status or DropConnection decides the response.
+//
+// For example, failing the third CreateMailbox with a 500:
+//
+//	s.AddFault(migadutest.Fault{
+//		Match:  migadutest.MatchRoute(http.MethodPost, "/v1/domains/*/mailboxes"),
+//		Call:   3,
+//		Status: http.StatusInternalServerError,
+//	})
+type Fault struct {
+	// Match selects requests. A nil Match matches every request.
+	Match Matcher
+	// Call, when set, applies the fault only to the Call-th matching request, counting from 1.
+	Call int
+	// Times, when set, limits how many matching requests the fault applies to.
+	Times int
+	// Delay holds the response back. It ends early when the request context is cancelled.
+	Delay time.Duration
+	// Status, when set, replaces the normal response and leaves the server state untouched.
+	Status int
+	// Header adds headers to a Status response, such as Retry-After.
+	Header http.Header
+	// Body replaces the default JSON error body of a Status response.
+	Body string
+	// DropConnection processes the request normally, then closes the connection halfway through the body.
+	DropConnection bool
+}
+
+type faultState struct {
+	fault   Fault
+	matched int
+	applied int
+}
+
+type faultOutcome struct {
+	delay  time.Duration
+	status int
+	header http.Header
+	body   string
+	drop   bool
+}
+
+func (o faultOutcome) response() response {
+	resp := apiError(o.status, "injected_fault", http.StatusText(o.status))
+	resp.header = o.header
+	if o.body != "" {
+		resp.raw = []byte(o.body)
+	}
+	return resp
+}
+
+// AddFault scripts a fault for subsequent requests.
+func (s *Server) AddFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fault.Header != nil {
+		fault.Header = fault.Header.Clone()
+	}
+	s.faults = append(s.faults, &faultState{fault: fault})
+}
+
+// ClearFaults removes every scripted fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+func (s *Server) matchFaults(r *http.Request) faultOutcome {
+	var outcome faultOutcome
+	decided := false
+	for _, state := range s.faults {
+		fault := state.fault
+		if fault.Match != nil && !fault.Match(r) {
+			continue
+		}
+		state.matched++
+		if fault.Call != 0 && state.matched != fault.Call {
+			continue
+		}
+		if fault.Times != 0 && state.applied >= fault.Times {
+			continue
+		}
+		state.applied++
+		outcome.delay += fault.Delay
+		if decided || (fault.Status == 0 && !fault.DropConnection) {
+			continue
+		}
+		decided = true
+		outcome.status = fault.Status
+		outcome.header = fault.Header
+		outcome.body = fault.Body
+		outcome.drop = fault.DropConnection
+	}
+	return outcome
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,81 @@
+package migadutest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	migadu "github.com/z-xavier/migadu-go"
+)
+
+func TestFaultFailsNthMatchingCall(t *testing.T) {
+	s := NewUnstartedServer()
+	s.AddDomain(migadu.Domain{Name: "example.com"})
+	s.AddFault(Fault{
+		Match:  MatchRoute(http.MethodPost, "/v1/domains/*/mailboxes"),
+		Call:   3,
+		Status: http.StatusInternalServerError,
+	})
+	client := newTestClient(t, s)
+	for i, localPart := range []string{"one", "two", "three", "four"} {
+		_, err := client.CreateMailbox(context.Background(), "example.com", migadu.CreateMailboxRequest{LocalPart: localPart, Name: localPart, Password: "x"})
+		if failed := migadu.IsServer(err); failed != (i == 2) {
+			t.Fatalf("CreateMailbox(%s) error = %v", localPart, err)
+		}
+	}
+	if _, found := s.Mailbox("example.com", "three"); found {
+		t.Fatal("failed request changed server state")
+	}
+}
+
+func TestFaultRateLimitsWithRetryAfter(t *testing.T) {
+	s := NewUnstartedServer()
+	s.AddDomain(migadu.Domain{Name: "example.com"})
+	s.AddFault(Fault{Times: 2, Status: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"0"}}})
+	client, err := s.Client(migadu.WithRetryPolicy(&migadu.RetryPolicy{MaxAttempts: 3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetDomain(context.Background(), "example.com"); err != nil {
+		t.Fatalf("GetDomain() error = %v", err)
+	}
+	if requests := s.Requests(); len(requests) != 3 {
+		t.Fatalf("requests = %d, want 3", len(requests))
+	}
+}
+
+func TestFaultDelaysRequests(t *testing.T) {
+	s := NewUnstartedServer()
+	s.AddDomain(migadu.Domain{Name: "example.com"})
+	s.AddFault(Fault{Match: MatchMethod(http.MethodGet), Delay: 200 * time.Millisecond})
+	client, err := s.Client(migadu.WithTimeout(20 * time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetDomain(context.Background(), "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetDomain() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestFaultDropsConnectionMidBody(t *testing.T) {
+	for _, started := range []bool{false, true} {
+		s := NewUnstartedServer()
+		if started {
+			s.Start()
+		}
+		s.AddDomain(migadu.Domain{Name: "example.com"})
+		s.AddFault(Fault{Times: 1, DropConnection: true})
+		client := newTestClient(t, s)
+		_, err := client.GetDomain(context.Background(), "example.com")
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("started = %v: GetDomain() error = %v, want unexpected EOF", started, err)
+		}
+		if _, err = client.GetDomain(context.Background(), "example.com"); err != nil {
+			t.Fatalf("started = %v: GetDomain() after fault error = %v", started, err)
+		}
+		s.Close()
+	}
+}
@@ -0,0 +1,167 @@
+package migadutest
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+
+	migadu "github.com/z-xavier/migadu-go"
+)
+
+// Resolver is an in-memory migadu.Resolver. Names are matched case-insensitively,
+// with or without a trailing dot, and unknown names fail like NXDOMAIN.
+type Resolver struct {
+	mu     sync.Mutex
+	mx     map[string][]*net.MX
+	txt    map[string][]string
+	cname  map[string]string
+	errors map[string]error
+}
+
+// NewResolver returns an empty resolver.
+func NewResolver() *Resolver {
+	return &Resolver{
+		mx:     map[string][]*net.MX{},
+		txt:    map[string][]string{},
+		cname:  map[string]string{},
+		errors: map[string]error{},
+	}
+}
+
+// AddMX publishes an MX record.
+func (r *Resolver) AddMX(name, host string, pref uint16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := dnsKey(name)
+	r.mx[key] = append(r.mx[key], &net.MX{Host: dnsKey(host) + ".", Pref: pref})
+}
+
+// AddTXT publishes TXT records.
+func (r *Resolver) AddTXT(name string, values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := dnsKey(name)
+	r.txt[key] = append(r.txt[key], values...)
+}
+
+// SetCNAME publishes a CNAME record.
+func (r *Resolver) SetCNAME(name, target string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cname[dnsKey(name)] = dnsKey(target) + "."
+}
+
+// SetError makes every lookup of name fail with err.
+func (r *Resolver) SetError(name string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors[dnsKey(name)] = err
+}
+
+// Remove deletes every record published at name.
+func (r *Resolver) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := dnsKey(name)
+	delete(r.mx, key)
+	delete(r.txt, key)
+	delete(r.cname, key)
+	delete(r.errors, key)
+}
+
+// Publish publishes every record in records exactly as Migadu requires them.
+func (r *Resolver) Publish(records migadu.DomainRecords) {
+	for _, record := range records.All() {
+		name := dnsKey(record.Name)
+		domain := dnsKey(records.DomainName)
+		switch {
+		case name == "" || name == "@":
+			name = domain
+		case name != domain && !strings.HasSuffix(name, "."+domain) && !strings.HasSuffix(record.Name, "."):
+			name += "." + domain
+		}
+		switch strings.ToUpper(record.Type) {
+		case "MX":
+			pref := 0
+			if record.Priority != nil {
+				pref = *record.Priority
+			}
+			r.AddMX(name, record.Value, uint16(pref))
+		case "CNAME":
+			r.SetCNAME(name, record.Value)
+		default:
+			r.AddTXT(name, record.Value)
+		}
+	}
+}
+
+// LookupMX implements migadu.Resolver.
+func (r *Resolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := dnsKey(name)
+	if err := r.lookupErr(ctx, key); err != nil {
+		return nil, err
+	}
+	records, ok := r.mx[key]
+	if !ok {
+		return nil, nxdomain(name)
+	}
+	copied := make([]*net.MX, len(records))
+	for i, mx := range records {
+		copied[i] = &net.MX{Host: mx.Host, Pref: mx.Pref}
+	}
+	return copied, nil
+}
+
+// LookupTXT implements migadu.Resolver.
+func (r *Resolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := dnsKey(name)
+	if err := r.lookupErr(ctx, key); err != nil {
+		return nil, err
+	}
+	records, ok := r.txt[key]
+	if !ok {
+		return nil, nxdomain(name)
+	}
+	return append([]string(nil), records...), nil
+}
+
+// LookupCNAME implements migadu.Resolver. Like net.Resolver, it returns the name
+// itself when the name exists without a CNAME record.
+func (r *Resolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := dnsKey(host)
+	if err := r.lookupErr(ctx, key); err != nil {
+		return "", err
+	}
+	if target, ok := r.cname[key]; ok {
+		return target, nil
+	}
+	if _, ok := r.txt[key]; ok {
+		return key + ".", nil
+	}
+	if _, ok := r.mx[key]; ok {
+		return key + ".", nil
+	}
+	return "", nxdomain(host)
+}
+
+func (r *Resolver) lookupErr(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.errors[key]
+}
+
+func nxdomain(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func dnsKey(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
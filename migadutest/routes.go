@@ -0,0 +1,724 @@
+package migadutest
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	migadu "github.com/z-xavier/migadu-go"
+)
+
+var localPartPattern = regexp.MustCompile(`^[a-zA-Z0-9!#$%&'*+/=?^_{|}~.-]+$`)
+
+type response struct {
+	status int
+	header http.Header
+	body   any
+	// raw, when set, is written verbatim instead of encoding body.
+	raw []byte
+}
+
+func (r response) encode() []byte {
+	if r.raw != nil {
+		return r.raw
+	}
+	data, _ := json.Marshal(r.body)
+	return append(data, '\n')
+}
+
+func ok(body any) response {
+	return response{status: http.StatusOK, body: body}
+}
+
+func apiError(status int, code, message string) response {
+	return response{status: status, body: map[string]string{"error": code, "message": message}}
+}
+
+func notFound(kind, name string) response {
+	return apiError(http.StatusNotFound, "not_found", fmt.Sprintf("%s %s not found", kind, name))
+}
+
+func fieldErrors(invalid map[string][]string) response {
+	return response{status: http.StatusUnprocessableEntity, body: map[string]any{"errors": invalid}}
+}
+
+func taken(field string) response {
+	return fieldErrors(map[string][]string{field: {"has already been taken"}})
+}
+
+func methodNotAllowed() response {
+	return apiError(http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed")
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	resp, outcome, err := s.handle(r)
+	if err != nil {
+		return
+	}
+	data := resp.encode()
+	writeHeader(w, resp, len(data))
+	if outcome.drop {
+		_, _ = w.Write(data[:len(data)/2])
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		// Aborting after a partial write makes net/http close the connection mid-body.
+		panic(http.ErrAbortHandler)
+	}
+	_, _ = w.Write(data)
+}
+
+func writeHeader(w http.ResponseWriter, resp response, length int) {
+	w.Header().Set("Content-Type", "application/json")
+	for key, values := range resp.header {
+		w.Header()[key] = values
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(length))
+	w.WriteHeader(resp.status)
+}
+
+// handle records r, applies scripted faults and, unless a fault replaces the response, routes it.
+func (s *Server) handle(r *http.Request) (response, faultOutcome, error) {
+	s.mu.Lock()
+	body := s.record(r)
+	outcome := s.matchFaults(r)
+	s.mu.Unlock()
+	if err := sleep(r.Context(), outcome.delay); err != nil {
+		return response{}, outcome, err
+	}
+	if outcome.status != 0 {
+		return outcome.response(), outcome, nil
+	}
+	s.mu.Lock()
+	resp := s.route(r, body)
+	s.mu.Unlock()
+	return resp, outcome, nil
+}
+
+func (s *Server) route(r *http.Request, body []byte) response {
+	if email, apiKey, authenticated := r.BasicAuth(); !authenticated || email != s.email || apiKey != s.apiKey {
+		return apiError(http.StatusUnauthorized, "unauthorized", "invalid credentials")
+	}
+	segments, err := splitPath(r.URL.EscapedPath())
+	if err != nil || len(segments) < 2 || segments[0] != "v1" || segments[1] != "domains" {
+		return apiError(http.StatusNotFound, "not_found", "no route for "+r.URL.Path)
+	}
+	segments = segments[2:]
+	if len(segments) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.listDomains()
+		case http.MethodPost:
+			return s.createDomain(body)
+		}
+		return methodNotAllowed()
+	}
+	state, found := s.domains[strings.ToLower(segments[0])]
+	if !found {
+		return notFound("domain", segments[0])
+	}
+	rest := segments[1:]
+	switch {
+	case len(rest) == 0:
+		switch r.Method {
+		case http.MethodGet:
+			return ok(state.domain)
+		case http.MethodPatch, http.MethodPut:
+			return s.updateDomain(state, body)
+		}
+		return methodNotAllowed()
+	case len(rest) == 1 && r.Method == http.MethodGet:
+		switch rest[0] {
+		case "records":
+			return ok(state.recordsOrDefault())
+		case "diagnostics":
+			return ok(state.diagnosticsOrDefault())
+		case "activate":
+			return s.activateDomain(state)
+		case "usage":
+			return ok(state.usageOrDefault())
+		}
+	}
+	switch rest[0] {
+	case "mailboxes":
+		return s.routeMailboxes(r.Method, state, rest[1:], body)
+	case "aliases":
+		return s.routeAliases(r.Method, state, rest[1:], body)
+	case "rewrites":
+		return s.routeRewrites(r.Method, state, rest[1:], body)
+	}
+	return apiError(http.StatusNotFound, "not_found", "no route for "+r.URL.Path)
+}
+
+func splitPath(path string) ([]string, error) {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, unescaped)
+	}
+	return segments, nil
+}
+
+// merge overlays the JSON fields of body on current, giving PUT/PATCH semantics
+// where absent fields keep their value and explicit zero values are applied.
+func merge[T any](current T, body []byte) (T, error) {
+	var result T
+	data, err := json.Marshal(current)
+	if err != nil {
+		return result, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return result, err
+	}
+	var overlay map[string]json.RawMessage
+	if err = json.Unmarshal(body, &overlay); err != nil {
+		return result, err
+	}
+	for key, value := range overlay {
+		fields[key] = value
+	}
+	if data, err = json.Marshal(fields); err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+func invalidJSON(err error) response {
+	return apiError(http.StatusBadRequest, "bad_request", "invalid JSON body: "+err.Error())
+}
+
+func (s *Server) timestamp() *migadu.Timestamp {
+	value := migadu.NewTimestamp(s.now().UTC().Truncate(time.Second))
+	return &value
+}
+
+func (s *Server) listDomains() response {
+	domains := make([]migadu.Domain, 0, len(s.domains))
+	for _, key := range sortedKeys(s.domains) {
+		domains = append(domains, s.domains[key].domain)
+	}
+	return ok(map[string]any{"domains": domains})
+}
+
+func (s *Server) createDomain(body []byte) response {
+	var request migadu.CreateDomainRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return invalidJSON(err)
+	}
+	name := strings.ToLower(strings.TrimSpace(request.Name))
+	if name == "" || !strings.Contains(name, ".") {
+		return fieldErrors(map[string][]string{"name": {"is invalid"}})
+	}
+	if _, exists := s.domains[name]; exists {
+		return apiError(http.StatusConflict, "conflict", "domain "+name+" already exists")
+	}
+	domain, err := merge(migadu.Domain{Name: name, State: "pending"}, body)
+	if err != nil {
+		return invalidJSON(err)
+	}
+	domain.Name = name
+	state := s.putDomain(domain)
+	if request.CreateDefaultAddresses != nil && *request.CreateDefaultAddresses {
+		for _, localPart := range []string{"postmaster", "abuse"} {
+			state.aliases[localPart] = &migadu.Alias{LocalPart: localPart, DomainName: name, Address: localPart + "@" + name}
+		}
+	}
+	return ok(state.domain)
+}
+
+func (s *Server) updateDomain(state *domainState, body []byte) response {
+	var request migadu.UpdateDomainRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return invalidJSON(err)
+	}
+	domain, err := merge(state.domain, body)
+	if err != nil {
+		return invalidJSON(err)
+	}
+	domain.Name = state.domain.Name
+	state.domain = domain
+	return ok(domain)
+}
+
+func (s *Server) activateDomain(state *domainState) response {
+	// Warnings and unknown statuses do not block activation.
+	for _, check := range state.diagnostics.FailingChecks() {
+		if check.Status == migadu.DiagnosticFail {
+			return apiError(http.StatusUnprocessableEntity, "dns_not_ready", check.String())
+		}
+	}
+	if state.domain.State != migadu.DomainStateActive {
+		state.domain.State = migadu.DomainStateActive
+		state.domain.ActivatedAt = s.timestamp()
+		state.domain.DeactivatedAt = nil
+	}
+	return ok(state.domain)
+}
+
+func (d *domainState) recordsOrDefault() migadu.DomainRecords {
+	if d.records != nil {
+		return *d.records
+	}
+	name := d.domain.Name
+	priority := func(value int) *int { return &value }
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name))
+	return migadu.DomainRecords{
+		DomainName: name,
+		MXRecords: []migadu.DNSRecord{
+			{Name: name, Type: "MX", Value: "aspmx1.migadu.com", Priority: priority(10)},
+			{Name: name, Type: "MX", Value: "aspmx2.migadu.com", Priority: priority(20)},
+		},
+		SPF: &migadu.DNSRecord{Name: name, Type: "TXT", Value: "v=spf1 include:spf.migadu.com -all"},
+		DKIM: []migadu.DNSRecord{
+			{Name: "key1._domainkey." + name, Type: "CNAME", Value: "key1." + name + "._domainkey.migadu.com"},
+			{Name: "key2._domainkey." + name, Type: "CNAME", Value: "key2." + name + "._domainkey.migadu.com"},
+			{Name: "key3._domainkey." + name, Type: "CNAME", Value: "key3." + name + "._domainkey.migadu.com"},
+		},
+		DMARC:           &migadu.DNSRecord{Name: "_dmarc." + name, Type: "TXT", Value: "v=DMARC1; p=quarantine;"},
+		DNSVerification: &migadu.DNSRecord{Name: name, Type: "TXT", Value: fmt.Sprintf("hosted-email-verify=%08x", hash.Sum32())},
+	}
+}
+
+func (d *domainState) diagnosticsOrDefault() migadu.DomainDiagnostics {
+	if d.diagnostics != nil {
+		return d.diagnostics
+	}
+	return migadu.DomainDiagnostics{}
+}
+
+func (d *domainState) usageOrDefault() migadu.DomainUsage {
+	if d.usage != nil {
+		return *d.usage
+	}
+	return migadu.DomainUsage{DomainName: d.domain.Name}
+}
+
+func validLocalPart(localPart string) bool {
+	return localPart != "" && localPartPattern.MatchString(localPart) &&
+		!strings.HasPrefix(localPart, ".") && !strings.HasSuffix(localPart, ".") && !strings.Contains(localPart, "..")
+}
+
+func validAddress(address string) bool {
+	localPart, domain, found := strings.Cut(address, "@")
+	return found && validLocalPart(localPart) && strings.Contains(domain, ".") && !strings.ContainsAny(domain, "@ ")
+}
+
+func (s *Server) routeMailboxes(method string, state *domainState, rest []string, body []byte) response {
+	if len(rest) == 0 {
+		switch method {
+		case http.MethodGet:
+			mailboxes := make([]migadu.Mailbox, 0, len(state.mailboxes))
+			for _, key := range sortedKeys(state.mailboxes) {
+				mailboxes = append(mailboxes, state.mailboxes[key].view())
+			}
+			return ok(map[string]any{"mailboxes": mailboxes})
+		case http.MethodPost:
+			return s.createMailbox(state, body)
+		}
+		return methodNotAllowed()
+	}
+	box, found := state.mailboxes[rest[0]]
+	if !found {
+		return notFound("mailbox", rest[0])
+	}
+	if len(rest) > 1 {
+		switch rest[1] {
+		case "identities":
+			return s.routeIdentities(method, state, box, rest[2:], body)
+		case "forwardings":
+			return s.routeForwardings(method, box, rest[2:], body)
+		}
+		return notFound("route", strings.Join(rest, "/"))
+	}
+	switch method {
+	case http.MethodGet:
+		return ok(box.view())
+	case http.MethodPut, http.MethodPatch:
+		var request migadu.UpdateMailboxRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			return invalidJSON(err)
+		}
+		mailbox, err := merge(box.mailbox, body)
+		if err != nil {
+			return invalidJSON(err)
+		}
+		mailbox.LocalPart, mailbox.DomainName, mailbox.Address = box.mailbox.LocalPart, box.mailbox.DomainName, box.mailbox.Address
+		mailbox.ChangedAt = *s.timestamp()
+		box.mailbox = mailbox
+		return ok(box.view())
+	case http.MethodDelete:
+		delete(state.mailboxes, rest[0])
+		return ok(box.view())
+	}
+	return methodNotAllowed()
+}
+
+func (s *Server) createMailbox(state *domainState, body []byte) response {
+	var request migadu.CreateMailboxRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return invalidJSON(err)
+	}
+	invalid := map[string][]string{}
+	if !validLocalPart(request.LocalPart) {
+		invalid["local_part"] = append(invalid["local_part"], "is invalid")
+	}
+	if strings.TrimSpace(request.Name) == "" {
+		invalid["name"] = append(invalid["name"], "can't be blank")
+	}
+	switch request.PasswordMethod {
+	case "invitation":
+		if request.PasswordRecoveryEmail == "" {
+			invalid["password_recovery_email"] = append(invalid["password_recovery_email"], "can't be blank")
+		}
+	case "", "password":
+		if request.Password == "" {
+			invalid["password"] = append(invalid["password"], "can't be blank")
+		}
+	default:
+		invalid["password_method"] = append(invalid["password_method"], "is not included in the list")
+	}
+	if len(invalid) > 0 {
+		return fieldErrors(invalid)
+	}
+	if _, exists := state.mailboxes[request.LocalPart]; exists {
+		return taken("local_part")
+	}
+	if _, exists := state.aliases[request.LocalPart]; exists {
+		return taken("local_part")
+	}
+	name := state.domain.Name
+	defaults := migadu.Mailbox{
+		LocalPart:            request.LocalPart,
+		DomainName:           name,
+		Address:              request.LocalPart + "@" + name,
+		MaySend:              true,
+		MayReceive:           true,
+		MayAccessImap:        true,
+		MayAccessPop3:        true,
+		MayAccessManagesieve: true,
+		SpamAction:           migadu.SpamActionFolder,
+		SpamAggressiveness:   migadu.SpamAggressivenessDefault,
+		ChangedAt:            *s.timestamp(),
+	}
+	mailbox, err := merge(defaults, body)
+	if err != nil {
+		return invalidJSON(err)
+	}
+	box := &mailboxState{mailbox: mailbox, identities: map[string]*migadu.Identity{}, forwardings: map[string]*migadu.Forwarding{}}
+	if request.ForwardingTo != "" {
+		box.forwardings[strings.ToLower(request.ForwardingTo)] = &migadu.Forwarding{Address: request.ForwardingTo, IsActive: true, ConfirmationSentAt: s.timestamp()}
+	}
+	state.mailboxes[request.LocalPart] = box
+	return ok(box.view())
+}
+
+// view returns the mailbox as the API shows it: with identities and without the password.
+func (m *mailboxState) view() migadu.Mailbox {
+	mailbox := m.mailbox
+	mailbox.Password = ""
+	mailbox.Identities = nil
+	for _, key := range sortedKeys(m.identities) {
+		identity := *m.identities[key]
+		identity.Password = ""
+		mailbox.Identities = append(mailbox.Identities, identity)
+	}
+	return mailbox
+}
+
+func identityView(identity *migadu.Identity) migadu.Identity {
+	view := *identity
+	view.Password = ""
+	return view
+}
+
+func (s *Server) routeIdentities(method string, state *domainState, box *mailboxState, rest []string, body []byte) response {
+	if len(rest) == 0 {
+		switch method {
+		case http.MethodGet:
+			identities := make([]migadu.Identity, 0, len(box.identities))
+			for _, key := range sortedKeys(box.identities) {
+				identities = append(identities, identityView(box.identities[key]))
+			}
+			return ok(map[string]any{"identities": identities})
+		case http.MethodPost:
+			var request migadu.CreateIdentityRequest
+			if err := json.Unmarshal(body, &request); err != nil {
+				return invalidJSON(err)
+			}
+			if !validLocalPart(request.LocalPart) {
+				return fieldErrors(map[string][]string{"local_part": {"is invalid"}})
+			}
+			if _, exists := box.identities[request.LocalPart]; exists {
+				return taken("local_part")
+			}
+			name := state.domain.Name
+			identity, err := merge(migadu.Identity{
+				LocalPart:  request.LocalPart,
+				DomainName: name,
+				Address:    request.LocalPart + "@" + name,
+				MaySend:    true,
+				MayReceive: true,
+			}, body)
+			if err != nil {
+				return invalidJSON(err)
+			}
+			box.identities[request.LocalPart] = &identity
+			return ok(identityView(&identity))
+		}
+		return methodNotAllowed()
+	}
+	identity, found := box.identities[rest[0]]
+	if !found || len(rest) > 1 {
+		return notFound("identity", rest[0])
+	}
+	switch method {
+	case http.MethodGet:
+		return ok(identityView(identity))
+	case http.MethodPut, http.MethodPatch:
+		var request migadu.UpdateIdentityRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			return invalidJSON(err)
+		}
+		updated, err := merge(*identity, body)
+		if err != nil {
+			return invalidJSON(err)
+		}
+		updated.LocalPart, updated.DomainName, updated.Address = identity.LocalPart, identity.DomainName, identity.Address
+		box.identities[rest[0]] = &updated
+		return ok(identityView(&updated))
+	case http.MethodDelete:
+		delete(box.identities, rest[0])
+		return ok(identityView(identity))
+	}
+	return methodNotAllowed()
+}
+
+func (s *Server) routeForwardings(method string, box *mailboxState, rest []string, body []byte) response {
+	if len(rest) == 0 {
+		switch method {
+		case http.MethodGet:
+			forwardings := make([]migadu.Forwarding, 0, len(box.forwardings))
+			for _, key := range sortedKeys(box.forwardings) {
+				forwardings = append(forwardings, *box.forwardings[key])
+			}
+			return ok(map[string]any{"forwardings": forwardings})
+		case http.MethodPost:
+			var request migadu.CreateForwardingRequest
+			if err := json.Unmarshal(body, &request); err != nil {
+				return invalidJSON(err)
+			}
+			if !validAddress(request.Address) {
+				return fieldErrors(map[string][]string{"address": {"is invalid"}})
+			}
+			key := strings.ToLower(request.Address)
+			if _, exists := box.forwardings[key]; exists {
+				return taken("address")
+			}
+			forwarding, err := merge(migadu.Forwarding{Address: request.Address, IsActive: true, ConfirmationSentAt: s.timestamp()}, body)
+			if err != nil {
+				return invalidJSON(err)
+			}
+			box.forwardings[key] = &forwarding
+			return ok(forwarding)
+		}
+		return methodNotAllowed()
+	}
+	key := strings.ToLower(rest[0])
+	forwarding, found := box.forwardings[key]
+	if !found || len(rest) > 1 {
+		return notFound("forwarding", rest[0])
+	}
+	switch method {
+	case http.MethodGet:
+		return ok(*forwarding)
+	case http.MethodPut, http.MethodPatch:
+		var request migadu.UpdateForwardingRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			return invalidJSON(err)
+		}
+		updated, err := merge(*forwarding, body)
+		if err != nil {
+			return invalidJSON(err)
+		}
+		updated.Address = forwarding.Address
+		box.forwardings[key] = &updated
+		return ok(updated)
+	case http.MethodDelete:
+		delete(box.forwardings, key)
+		return ok(*forwarding)
+	}
+	return methodNotAllowed()
+}
+
+func validDestinations(destinations []string) []string {
+	if len(destinations) == 0 {
+		return []string{"can't be blank"}
+	}
+	for _, destination := range destinations {
+		if !validAddress(destination) {
+			return []string{destination + " is not a valid address"}
+		}
+	}
+	return nil
+}
+
+func (s *Server) routeAliases(method string, state *domainState, rest []string, body []byte) response {
+	if len(rest) == 0 {
+		switch method {
+		case http.MethodGet:
+			aliases := make([]migadu.Alias, 0, len(state.aliases))
+			for _, key := range sortedKeys(state.aliases) {
+				aliases = append(aliases, *state.aliases[key])
+			}
+			return ok(map[string]any{"address_aliases": aliases})
+		case http.MethodPost:
+			var request migadu.CreateAliasRequest
+			if err := json.Unmarshal(body, &request); err != nil {
+				return invalidJSON(err)
+			}
+			invalid := map[string][]string{}
+			if !validLocalPart(request.LocalPart) {
+				invalid["local_part"] = []string{"is invalid"}
+			}
+			if problems := validDestinations(request.Destinations); problems != nil {
+				invalid["destinations"] = problems
+			}
+			if len(invalid) > 0 {
+				return fieldErrors(invalid)
+			}
+			if _, exists := state.aliases[request.LocalPart]; exists {
+				return taken("local_part")
+			}
+			if _, exists := state.mailboxes[request.LocalPart]; exists {
+				return taken("local_part")
+			}
+			name := state.domain.Name
+			alias, err := merge(migadu.Alias{LocalPart: request.LocalPart, DomainName: name, Address: request.LocalPart + "@" + name}, body)
+			if err != nil {
+				return invalidJSON(err)
+			}
+			state.aliases[request.LocalPart] = &alias
+			return ok(alias)
+		}
+		return methodNotAllowed()
+	}
+	alias, found := state.aliases[rest[0]]
+	if !found || len(rest) > 1 {
+		return notFound("alias", rest[0])
+	}
+	switch method {
+	case http.MethodGet:
+		return ok(*alias)
+	case http.MethodPut, http.MethodPatch:
+		var request migadu.UpdateAliasRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			return invalidJSON(err)
+		}
+		if request.Destinations != nil {
+			if problems := validDestinations(*request.Destinations); problems != nil {
+				return fieldErrors(map[string][]string{"destinations": problems})
+			}
+		}
+		updated, err := merge(*alias, body)
+		if err != nil {
+			return invalidJSON(err)
+		}
+		updated.LocalPart, updated.DomainName, updated.Address = alias.LocalPart, alias.DomainName, alias.Address
+		state.aliases[rest[0]] = &updated
+		return ok(updated)
+	case http.MethodDelete:
+		delete(state.aliases, rest[0])
+		return ok(*alias)
+	}
+	return methodNotAllowed()
+}
+
+func (s *Server) routeRewrites(method string, state *domainState, rest []string, body []byte) response {
+	if len(rest) == 0 {
+		switch method {
+		case http.MethodGet:
+			rewrites := make([]migadu.Rewrite, 0, len(state.rewrites))
+			for _, rewrite := range state.rewrites {
+				rewrites = append(rewrites, *rewrite)
+			}
+			sort.Slice(rewrites, func(i, j int) bool {
+				if rewrites[i].OrderNum != rewrites[j].OrderNum {
+					return rewrites[i].OrderNum < rewrites[j].OrderNum
+				}
+				return rewrites[i].Name < rewrites[j].Name
+			})
+			return ok(map[string]any{"rewrites": rewrites})
+		case http.MethodPost:
+			var request migadu.CreateRewriteRequest
+			if err := json.Unmarshal(body, &request); err != nil {
+				return invalidJSON(err)
+			}
+			invalid := map[string][]string{}
+			if strings.TrimSpace(request.Name) == "" {
+				invalid["name"] = []string{"can't be blank"}
+			}
+			if request.LocalPartRule == "" {
+				invalid["local_part_rule"] = []string{"can't be blank"}
+			}
+			if problems := validDestinations(request.Destinations); problems != nil {
+				invalid["destinations"] = problems
+			}
+			if len(invalid) > 0 {
+				return fieldErrors(invalid)
+			}
+			if _, exists := state.rewrites[request.Name]; exists {
+				return taken("name")
+			}
+			rewrite, err := merge(migadu.Rewrite{DomainName: state.domain.Name, OrderNum: len(state.rewrites) + 1}, body)
+			if err != nil {
+				return invalidJSON(err)
+			}
+			state.rewrites[rewrite.Name] = &rewrite
+			return ok(rewrite)
+		}
+		return methodNotAllowed()
+	}
+	rewrite, found := state.rewrites[rest[0]]
+	if !found || len(rest) > 1 {
+		return notFound("rewrite", rest[0])
+	}
+	switch method {
+	case http.MethodGet:
+		return ok(*rewrite)
+	case http.MethodPut, http.MethodPatch:
+		var request migadu.UpdateRewriteRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			return invalidJSON(err)
+		}
+		updated, err := merge(*rewrite, body)
+		if err != nil {
+			return invalidJSON(err)
+		}
+		updated.DomainName = rewrite.DomainName
+		if updated.Name != rewrite.Name {
+			if _, exists := state.rewrites[updated.Name]; exists {
+				return taken("name")
+			}
+			delete(state.rewrites, rewrite.Name)
+		}
+		state.rewrites[updated.Name] = &updated
+		return ok(updated)
+	case http.MethodDelete:
+		delete(state.rewrites, rest[0])
+		return ok(*rewrite)
+	}
+	return methodNotAllowed()
+}
@@ -0,0 +1,338 @@
+// Package migadutest provides an in-memory fake of the Migadu API for tests.
+//
+// The fake keeps state, so a create followed by a list returns the new object,
+// and answers with realistic 401, 404, 409 and 422 errors. Tests can seed
+// fixtures and inspect every request the server received.
+package migadutest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	migadu "github.com/z-xavier/migadu-go"
+)
+
+const (
+	DefaultEmail  = "admin@example.com"
+	DefaultAPIKey = "secret"
+)
+
+// Request is a request received by the fake server.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// Server is a stateful fake Migadu API. The zero value is not usable; create one with NewServer.
+type Server struct {
+	// URL is the base URL of the running server, suitable for migadu.WithBaseURL.
+	URL string
+
+	server *httptest.Server
+	now    func() time.Time
+
+	mu       sync.Mutex
+	email    string
+	apiKey   string
+	domains  map[string]*domainState
+	requests []Request
+	faults   []*faultState
+}
+
+type domainState struct {
+	domain      migadu.Domain
+	records     *migadu.DomainRecords
+	diagnostics migadu.DomainDiagnostics
+	usage       *migadu.DomainUsage
+	mailboxes   map[string]*mailboxState
+	aliases     map[string]*migadu.Alias
+	rewrites    map[string]*migadu.Rewrite
+}
+
+type mailboxState struct {
+	mailbox     migadu.Mailbox
+	identities  map[string]*migadu.Identity
+	forwardings map[string]*migadu.Forwarding
+}
+
+// NewServer starts a fake server accepting DefaultEmail and DefaultAPIKey.
+// Callers should Close it when done.
+func NewServer() *Server {
+	s := NewUnstartedServer()
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a fake that is not listening yet. It can be used
+// in-process through Handler or Doer, or started later with Start.
+func NewUnstartedServer() *Server {
+	return &Server{
+		now:     time.Now,
+		email:   DefaultEmail,
+		apiKey:  DefaultAPIKey,
+		domains: map[string]*domainState{},
+	}
+}
+
+// Start starts listening on a local port and sets URL.
+func (s *Server) Start() {
+	s.server = httptest.NewServer(s.Handler())
+	s.URL = s.server.URL
+}
+
+// Close shuts down the server if it was started.
+func (s *Server) Close() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+// SetCredentials changes the Basic Auth credentials the server accepts.
+func (s *Server) SetCredentials(email, apiKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.email = email
+	s.apiKey = apiKey
+}
+
+// Handler returns the http.Handler implementing the fake API.
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(s.serveHTTP)
+}
+
+// Doer returns an HTTPDoer that serves requests in-process without a network listener.
+// Scripted faults apply as they do over the network; a dropped connection surfaces
+// as io.ErrUnexpectedEOF while reading the body.
+func (s *Server) Doer() migadu.HTTPDoer {
+	return migadu.HTTPDoerFunc(func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		resp, outcome, err := s.handle(req)
+		if err != nil {
+			return nil, err
+		}
+		data := resp.encode()
+		recorder := httptest.NewRecorder()
+		writeHeader(recorder, resp, len(data))
+		result := recorder.Result()
+		var body io.Reader = bytes.NewReader(data)
+		if outcome.drop {
+			body = io.MultiReader(bytes.NewReader(data[:len(data)/2]), errReader{io.ErrUnexpectedEOF})
+		}
+		result.Body = io.NopCloser(body)
+		return result, nil
+	})
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// Client returns a client authenticated with the server's credentials. It talks
+// to the listening server when started, and in-process otherwise.
+func (s *Server) Client(options ...migadu.Option) (*migadu.Client, error) {
+	s.mu.Lock()
+	email, apiKey := s.email, s.apiKey
+	s.mu.Unlock()
+	defaults := []migadu.Option{migadu.WithBaseURL("https://api.migadu.test"), migadu.WithHTTPClient(s.Doer())}
+	if s.server != nil {
+		defaults = []migadu.Option{migadu.WithBaseURL(s.URL), migadu.WithHTTPClient(s.server.Client())}
+	}
+	return migadu.NewWithOptions(email, apiKey, append(defaults, options...)...)
+}
+
+// Requests returns a copy of every request received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requests := make([]Request, len(s.requests))
+	copy(requests, s.requests)
+	return requests
+}
+
+// ResetRequests clears the request log.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// AddDomain seeds a domain. An empty State defaults to migadu.DomainStateActive.
+func (s *Server) AddDomain(domain migadu.Domain) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if domain.State == "" {
+		domain.State = migadu.DomainStateActive
+	}
+	s.putDomain(domain)
+}
+
+// AddMailbox seeds a mailbox, creating its domain if needed.
+func (s *Server) AddMailbox(domain string, mailbox migadu.Mailbox) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.ensureDomain(domain)
+	mailbox.DomainName = domain
+	mailbox.Address = mailbox.LocalPart + "@" + domain
+	identities := mailbox.Identities
+	mailbox.Identities = nil
+	box := &mailboxState{mailbox: mailbox, identities: map[string]*migadu.Identity{}, forwardings: map[string]*migadu.Forwarding{}}
+	for i := range identities {
+		identity := identities[i]
+		identity.DomainName = domain
+		identity.Address = identity.LocalPart + "@" + domain
+		box.identities[identity.LocalPart] = &identity
+	}
+	state.mailboxes[mailbox.LocalPart] = box
+}
+
+// AddIdentity seeds an identity on an existing mailbox, creating the mailbox if needed.
+func (s *Server) AddIdentity(domain, mailbox string, identity migadu.Identity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	box := s.ensureMailbox(domain, mailbox)
+	identity.DomainName = domain
+	identity.Address = identity.LocalPart + "@" + domain
+	box.identities[identity.LocalPart] = &identity
+}
+
+// AddForwarding seeds a forwarding on a mailbox, creating the mailbox if needed.
+func (s *Server) AddForwarding(domain, mailbox string, forwarding migadu.Forwarding) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	box := s.ensureMailbox(domain, mailbox)
+	box.forwardings[strings.ToLower(forwarding.Address)] = &forwarding
+}
+
+// AddAlias seeds an alias, creating its domain if needed.
+func (s *Server) AddAlias(domain string, alias migadu.Alias) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.ensureDomain(domain)
+	alias.DomainName = domain
+	alias.Address = alias.LocalPart + "@" + domain
+	state.aliases[alias.LocalPart] = &alias
+}
+
+// AddRewrite seeds a rewrite, creating its domain if needed.
+func (s *Server) AddRewrite(domain string, rewrite migadu.Rewrite) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.ensureDomain(domain)
+	rewrite.DomainName = domain
+	state.rewrites[rewrite.Name] = &rewrite
+}
+
+// SetDomainRecords overrides the DNS records returned for a domain.
+func (s *Server) SetDomainRecords(domain string, records migadu.DomainRecords) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records.DomainName = domain
+	s.ensureDomain(domain).records = &records
+}
+
+// SetDomainDiagnostics sets the diagnostics returned for a domain.
+// ActivateDomain fails with a 422 while any of their checks is failing.
+func (s *Server) SetDomainDiagnostics(domain string, diagnostics migadu.DomainDiagnostics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ensureDomain(domain).diagnostics = diagnostics
+}
+
+// SetDomainUsage sets the usage returned for a domain.
+func (s *Server) SetDomainUsage(domain string, usage migadu.DomainUsage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	usage.DomainName = domain
+	s.ensureDomain(domain).usage = &usage
+}
+
+// Domain returns the stored domain, if any.
+func (s *Server) Domain(name string) (migadu.Domain, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.domains[strings.ToLower(name)]
+	if !ok {
+		return migadu.Domain{}, false
+	}
+	return state.domain, true
+}
+
+// Mailbox returns the stored mailbox, including its password, if any.
+func (s *Server) Mailbox(domain, localPart string) (migadu.Mailbox, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.domains[strings.ToLower(domain)]
+	if !ok {
+		return migadu.Mailbox{}, false
+	}
+	box, ok := state.mailboxes[localPart]
+	if !ok {
+		return migadu.Mailbox{}, false
+	}
+	return box.mailbox, true
+}
+
+func (s *Server) putDomain(domain migadu.Domain) *domainState {
+	key := strings.ToLower(domain.Name)
+	state, ok := s.domains[key]
+	if !ok {
+		state = &domainState{
+			mailboxes: map[string]*mailboxState{},
+			aliases:   map[string]*migadu.Alias{},
+			rewrites:  map[string]*migadu.Rewrite{},
+		}
+		s.domains[key] = state
+	}
+	state.domain = domain
+	return state
+}
+
+func (s *Server) ensureDomain(name string) *domainState {
+	if state, ok := s.domains[strings.ToLower(name)]; ok {
+		return state
+	}
+	return s.putDomain(migadu.Domain{Name: name, State: migadu.DomainStateActive})
+}
+
+func (s *Server) ensureMailbox(domain, localPart string) *mailboxState {
+	state := s.ensureDomain(domain)
+	if box, ok := state.mailboxes[localPart]; ok {
+		return box
+	}
+	box := &mailboxState{
+		mailbox:     migadu.Mailbox{LocalPart: localPart, DomainName: domain, Address: localPart + "@" + domain},
+		identities:  map[string]*migadu.Identity{},
+		forwardings: map[string]*migadu.Forwarding{},
+	}
+	state.mailboxes[localPart] = box
+	return box
+}
+
+func (s *Server) record(r *http.Request) []byte {
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone(), Body: body})
+	return body
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+package migadutest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	migadu "github.com/z-xavier/migadu-go"
+)
+
+func newTestClient(t *testing.T, s *Server) *migadu.Client {
+	t.Helper()
+	client, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestServerKeepsState(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	if _, err := client.CreateDomain(ctx, migadu.CreateDomainRequest{Name: "example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	mailbox, err := client.CreateMailbox(ctx, "example.com", migadu.CreateMailboxRequest{LocalPart: "demo", Name: "Demo", Password: "hunter2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mailbox.Address != "demo@example.com" || mailbox.Password != "" {
+		t.Fatalf("CreateMailbox() = %+v", mailbox)
+	}
+	maySend := false
+	if _, err = client.UpdateMailbox(ctx, "example.com", "demo", migadu.UpdateMailboxRequest{MaySend: &maySend}); err != nil {
+		t.Fatal(err)
+	}
+	mailboxes, err := client.ListMailboxes(ctx, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mailboxes) != 1 || mailboxes[0].MaySend || !mailboxes[0].MayReceive || mailboxes[0].Name != "Demo" {
+		t.Fatalf("ListMailboxes() = %+v", mailboxes)
+	}
+	if stored, _ := s.Mailbox("example.com", "demo"); stored.Password != "hunter2" {
+		t.Fatalf("stored password = %q", stored.Password)
+	}
+
+	if _, err = client.CreateIdentity(ctx, "example.com", "demo", migadu.CreateIdentityRequest{LocalPart: "sales"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.CreateForwarding(ctx, "example.com", "demo", migadu.CreateForwardingRequest{Address: "outside@example.net"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.CreateAlias(ctx, "example.com", migadu.CreateAliasRequest{LocalPart: "info", Destinations: []string{"demo@example.com"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.CreateRewrite(ctx, "example.com", migadu.CreateRewriteRequest{Name: "demo", LocalPartRule: "demo-*", Destinations: []string{"demo@example.com"}}); err != nil {
+		t.Fatal(err)
+	}
+	identities, _ := client.ListIdentities(ctx, "example.com", "demo")
+	forwardings, _ := client.ListForwardings(ctx, "example.com", "demo")
+	aliases, _ := client.ListAliases(ctx, "example.com")
+	rewrites, _ := client.ListRewrites(ctx, "example.com")
+	if len(identities) != 1 || len(forwardings) != 1 || len(aliases) != 1 || len(rewrites) != 1 {
+		t.Fatalf("identities = %d, forwardings = %d, aliases = %d, rewrites = %d", len(identities), len(forwardings), len(aliases), len(rewrites))
+	}
+
+	if err = client.DeleteAlias(ctx, "example.com", "info"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetAlias(ctx, "example.com", "info"); !migadu.IsNotFound(err) {
+		t.Fatalf("GetAlias() after delete error = %v", err)
+	}
+}
+
+func TestServerReturnsRealisticErrors(t *testing.T) {
+	s := NewUnstartedServer()
+	s.AddMailbox("example.com", migadu.Mailbox{LocalPart: "demo"})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	if _, err := client.GetMailbox(ctx, "example.com", "missing"); !migadu.IsNotFound(err) {
+		t.Fatalf("missing mailbox error = %v", err)
+	}
+	if _, err := client.GetDomain(ctx, "missing.example"); !migadu.IsNotFound(err) {
+		t.Fatalf("missing domain error = %v", err)
+	}
+	_, err := client.CreateMailbox(ctx, "example.com", migadu.CreateMailboxRequest{LocalPart: "demo", Name: "Demo", Password: "x"})
+	if !migadu.IsConflict(err) {
+		t.Fatalf("duplicate mailbox error = %v", err)
+	}
+	if _, err = client.CreateDomain(ctx, migadu.CreateDomainRequest{Name: "example.com"}); !migadu.IsConflict(err) {
+		t.Fatalf("duplicate domain error = %v", err)
+	}
+	// The fake validates like the API, so skip local validation to reach it.
+	_, err = client.With(migadu.WithoutValidation()).CreateAlias(ctx, "example.com", migadu.CreateAliasRequest{LocalPart: "bad local", Destinations: nil})
+	var apiErr *migadu.APIError
+	if !migadu.IsValidation(err) || !errors.As(err, &apiErr) || len(apiErr.FieldErrors) != 2 {
+		t.Fatalf("invalid alias error = %v", err)
+	}
+
+	wrong, err := s.Client(migadu.WithCredentialsProvider(migadu.StaticCredentials(DefaultEmail, "wrong")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = wrong.ListDomains(ctx); !migadu.IsUnauthorized(err) {
+		t.Fatalf("bad credentials error = %v", err)
+	}
+}
+
+func TestServerRecordsRequests(t *testing.T) {
+	s := NewUnstartedServer()
+	s.AddDomain(migadu.Domain{Name: "example.com"})
+	client := newTestClient(t, s)
+	ctx := context.Background()
+	if _, err := client.GetDomainRecords(ctx, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	domain, err := client.ActivateDomain(ctx, "example.com")
+	if err != nil || domain.State != migadu.DomainStateActive {
+		t.Fatalf("ActivateDomain() = %+v, %v", domain, err)
+	}
+	requests := s.Requests()
+	if len(requests) != 2 || requests[0].Method != http.MethodGet || requests[1].Path != "/v1/domains/example.com/activate" {
+		t.Fatalf("requests = %+v", requests)
+	}
+}
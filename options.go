@@ -0,0 +1,132 @@
+package migadu
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Client built by NewWithOptions or derived with Client.With.
+type Option func(*Client)
+
+// WithBaseURL sets the API host, for example a test server URL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the transport used to execute requests.
+func WithHTTPClient(doer HTTPDoer) Option {
+	return func(c *Client) {
+		c.httpClient = doer
+	}
+}
+
+// WithTimeout sets the timeout covering each call including its retries. Zero disables it.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithLogger logs every request through logger. A nil logger disables logging.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithRetryPolicy sets the retry policy. A nil policy disables retries.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) {
+		if policy != nil {
+			copied := *policy
+			policy = &copied
+		}
+		c.retry = policy
+	}
+}
+
+// WithRateLimiter sets the limiter shared by every request. Clients derived with
+// With keep sharing the same limiter unless it is replaced.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithMiddleware appends middlewares after those already configured.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware[:len(c.middleware):len(c.middleware)], middlewares...)
+	}
+}
+
+// WithHeader adds default headers sent with every request, replacing earlier values for the same keys.
+func WithHeader(header http.Header) Option {
+	return func(c *Client) {
+		if c.header == nil {
+			c.header = http.Header{}
+		}
+		for key, values := range header {
+			c.header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+}
+
+// NewWithOptions creates a Migadu API client without making a network request.
+func NewWithOptions(email, apiKey string, options ...Option) (*Client, error) {
+	if strings.TrimSpace(email) == "" {
+		return nil, ErrEmailRequired
+	}
+	if strings.TrimSpace(apiKey) == "" {
+		return nil, ErrAPIKeyRequired
+	}
+	c := newClient(StaticCredentials(email, apiKey))
+	for _, option := range options {
+		option(c)
+	}
+	return c, nil
+}
+
+func newClient(credentials CredentialsProvider) *Client {
+	return &Client{
+		baseURL:     defaultAPIHost,
+		timeout:     DefaultTimeout,
+		httpClient:  http.DefaultClient,
+		userAgent:   DefaultUserAgent,
+		credentials: credentials,
+	}
+}
+
+// Clone returns a copy of c that shares no mutable configuration with it,
+// except the rate limiter, which stays shared so both clients draw from one budget.
+func (c *Client) Clone() *Client {
+	clone := *c
+	clone.header = c.header.Clone()
+	clone.middleware = append([]Middleware(nil), c.middleware...)
+	if c.retry != nil {
+		retry := *c.retry
+		clone.retry = &retry
+	}
+	return &clone
+}
+
+// With returns a copy of c with options applied, leaving c unchanged.
+// For example, c.With(WithTimeout(2*time.Second)) derives a client for health checks.
+func (c *Client) With(options ...Option) *Client {
+	clone := c.Clone()
+	for _, option := range options {
+		option(clone)
+	}
+	return clone
+}
@@ -0,0 +1,77 @@
+package migadu
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWithOptionsConfiguresClient(t *testing.T) {
+	var got *http.Request
+	doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+		got = req
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+	})
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doer),
+		WithTimeout(time.Second),
+		WithUserAgent("provisioner/1.0"),
+		WithHeader(http.Header{"x-request-source": []string{"nightly"}}),
+		WithRetryPolicy(DefaultRetryPolicy()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetDomain(context.Background(), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if got.URL.Host != "api.test" || got.Header.Get("User-Agent") != "provisioner/1.0" || got.Header.Get("X-Request-Source") != "nightly" {
+		t.Fatalf("request = %s %v", got.URL, got.Header)
+	}
+	if client.Timeout() != time.Second || client.retry == nil || client.retry.MaxAttempts != DefaultRetryMaxAttempts {
+		t.Fatalf("client = %+v", client)
+	}
+}
+
+func TestNewSendsDefaultUserAgent(t *testing.T) {
+	client, err := New("admin@example.com", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := client.getV1ReqBuilder().SetMethod(http.MethodGet).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("User-Agent"); got != DefaultUserAgent {
+		t.Fatalf("User-Agent = %q", got)
+	}
+}
+
+func TestWithDerivesIndependentClient(t *testing.T) {
+	base, err := NewWithOptions("admin@example.com", "secret",
+		WithHeader(http.Header{"X-Team": []string{"mail"}}),
+		WithMiddleware(UserAgentMiddleware("base")),
+		WithRateLimiter(NewRateLimiter(10, 1)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	health := base.With(
+		WithTimeout(2*time.Second),
+		WithHeader(http.Header{"X-Team": []string{"health"}}),
+		WithMiddleware(TimeoutMiddleware(time.Second)),
+	)
+	if base.timeout != DefaultTimeout || base.header.Get("X-Team") != "mail" || len(base.middleware) != 1 {
+		t.Fatalf("With() changed the original client: %+v", base)
+	}
+	if health.timeout != 2*time.Second || health.header.Get("X-Team") != "health" || len(health.middleware) != 2 {
+		t.Fatalf("derived client = %+v", health)
+	}
+	if health.limiter != base.limiter || health.credentials != base.credentials {
+		t.Fatal("derived client lost shared limiter or credentials")
+	}
+}
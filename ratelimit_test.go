@@ -0,0 +1,74 @@
+package migadu
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllowsBurstThenThrottles(t *testing.T) {
+	limiter := NewRateLimiter(10, 2)
+	now := time.Now()
+	if d := limiter.reserve(now); d != 0 {
+		t.Fatalf("first reserve delay = %s", d)
+	}
+	if d := limiter.reserve(now); d != 0 {
+		t.Fatalf("second reserve delay = %s", d)
+	}
+	if d := limiter.reserve(now); d < 90*time.Millisecond || d > 110*time.Millisecond {
+		t.Fatalf("third reserve delay = %s, want about 100ms", d)
+	}
+}
+
+func TestRateLimiterWaitUnblocksOnCancel(t *testing.T) {
+	limiter := NewRateLimiter(0.001, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+	}()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() error = %v, want context canceled", err)
+	}
+}
+
+func TestClientLimiterIsSharedAcrossGoroutines(t *testing.T) {
+	var mu sync.Mutex
+	var sent []time.Time
+	client := &Client{
+		baseURL: "https://api.test",
+		limiter: NewRateLimiter(100, 1),
+		httpClient: doerFunc(func(*http.Request) (*http.Response, error) {
+			mu.Lock()
+			sent = append(sent, time.Now())
+			mu.Unlock()
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		}),
+	}
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetMailbox(context.Background(), "example.com", "demo"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(sent) != 5 {
+		t.Fatalf("sent %d requests", len(sent))
+	}
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Fatalf("5 requests at 100/s with burst 1 finished in %s", elapsed)
+	}
+}
@@ -0,0 +1,82 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	migadu "github.com/z-xavier/migadu-go"
+)
+
+// ApplyError reports the change Apply stopped at. The changes before it were applied.
+type ApplyError struct {
+	Change  Change
+	Applied int
+	Err     error
+}
+
+func (e *ApplyError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Change, e.Err)
+}
+
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// Apply makes the changes of plan in order and stops at the first error, which
+// is an *ApplyError. A client with migadu.WithDryRun records the requests instead.
+func Apply(ctx context.Context, client *migadu.Client, plan *Plan) error {
+	for i, change := range plan.Changes {
+		if err := applyChange(ctx, client, change); err != nil {
+			return &ApplyError{Change: change, Applied: i, Err: err}
+		}
+	}
+	return nil
+}
+
+func applyChange(ctx context.Context, client *migadu.Client, change Change) error {
+	if change.Action == ActionDelete {
+		switch change.Kind {
+		case KindMailbox:
+			return client.DeleteMailbox(ctx, change.Domain, change.Key)
+		case KindIdentity:
+			return client.DeleteIdentity(ctx, change.Domain, change.Mailbox, change.Key)
+		case KindForwarding:
+			return client.DeleteForwarding(ctx, change.Domain, change.Mailbox, change.Key)
+		case KindAlias:
+			return client.DeleteAlias(ctx, change.Domain, change.Key)
+		case KindRewrite:
+			return client.DeleteRewrite(ctx, change.Domain, change.Key)
+		}
+		return fmt.Errorf("cannot delete a %s", change.Kind)
+	}
+	var err error
+	switch request := change.Request.(type) {
+	case migadu.CreateDomainRequest:
+		_, err = client.CreateDomain(ctx, request)
+	case migadu.UpdateDomainRequest:
+		_, err = client.UpdateDomain(ctx, change.Domain, request)
+	case migadu.CreateMailboxRequest:
+		_, err = client.CreateMailbox(ctx, change.Domain, request)
+	case migadu.UpdateMailboxRequest:
+		_, err = client.UpdateMailbox(ctx, change.Domain, change.Key, request)
+	case migadu.CreateIdentityRequest:
+		_, err = client.CreateIdentity(ctx, change.Domain, change.Mailbox, request)
+	case migadu.UpdateIdentityRequest:
+		_, err = client.UpdateIdentity(ctx, change.Domain, change.Mailbox, change.Key, request)
+	case migadu.CreateForwardingRequest:
+		_, err = client.CreateForwarding(ctx, change.Domain, change.Mailbox, request)
+	case migadu.UpdateForwardingRequest:
+		_, err = client.UpdateForwarding(ctx, change.Domain, change.Mailbox, change.Key, request)
+	case migadu.CreateAliasRequest:
+		_, err = client.CreateAlias(ctx, change.Domain, request)
+	case migadu.UpdateAliasRequest:
+		_, err = client.UpdateAlias(ctx, change.Domain, change.Key, request)
+	case migadu.CreateRewriteRequest:
+		_, err = client.CreateRewrite(ctx, change.Domain, request)
+	case migadu.UpdateRewriteRequest:
+		_, err = client.UpdateRewrite(ctx, change.Domain, change.Key, request)
+	default:
+		err = fmt.Errorf("unsupported request %T", request)
+	}
+	return err
+}
@@ -0,0 +1,171 @@
+package reconcile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// createOnly fields are sent when a resource is created but cannot be read back.
+var createOnly = map[string]bool{"password": true, "password_method": true, "create_default_addresses": true}
+
+// addressLists are compared ignoring case and order, which the API does not preserve.
+var addressLists = map[string]bool{
+	"destinations":          true,
+	"catchall_destinations": true,
+	"delegations":           true,
+	"recipient_denylist":    true,
+	"sender_allowlist":      true,
+	"sender_denylist":       true,
+}
+
+// FieldDiff is a field whose live value differs from the document, with both
+// values as decoded JSON. From is nil when the resource is created or the API omits the field.
+type FieldDiff struct {
+	Field string
+	From  any
+	To    any
+}
+
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Field, formatValue(d.From), formatValue(d.To))
+}
+
+func formatValue(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// fields returns the JSON fields v sets.
+func fields(v any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]json.RawMessage
+	if err = json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// convert decodes fields into a request type.
+func convert[T any](fields map[string]json.RawMessage) (T, error) {
+	var result T
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// compare returns the fields of want, other than skip and createOnly, whose value
+// differs in have. A field the API omits equals the empty value of its type, and
+// address lists are compared as sets of lower-cased addresses.
+func compare(want, have map[string]json.RawMessage, skip string) ([]FieldDiff, map[string]json.RawMessage) {
+	var diffs []FieldDiff
+	changed := map[string]json.RawMessage{}
+	for _, name := range sortedFields(want) {
+		if name == skip || createOnly[name] {
+			continue
+		}
+		to, from := decodeValue(want[name]), decodeValue(have[name])
+		if isEmpty(to) && isEmpty(from) || reflect.DeepEqual(normalizeValue(name, to), normalizeValue(name, from)) {
+			continue
+		}
+		diffs = append(diffs, FieldDiff{Field: name, From: from, To: to})
+		changed[name] = want[name]
+	}
+	return diffs, changed
+}
+
+// created describes the fields of a new resource, other than skip and createOnly.
+func created(want map[string]json.RawMessage, skip string) []FieldDiff {
+	var diffs []FieldDiff
+	for _, name := range sortedFields(want) {
+		if name != skip && !createOnly[name] {
+			diffs = append(diffs, FieldDiff{Field: name, To: decodeValue(want[name])})
+		}
+	}
+	return diffs
+}
+
+// split separates the fields a request type accepts from the rest.
+func split(want map[string]json.RawMessage, t reflect.Type) (accepted, rest map[string]json.RawMessage) {
+	known := map[string]bool{}
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		known[name] = true
+	}
+	accepted, rest = map[string]json.RawMessage{}, map[string]json.RawMessage{}
+	for name, value := range want {
+		if known[name] {
+			accepted[name] = value
+		} else {
+			rest[name] = value
+		}
+	}
+	return accepted, rest
+}
+
+func decodeValue(data json.RawMessage) any {
+	if data == nil {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return string(data)
+	}
+	return value
+}
+
+// normalizeValue returns an address list sorted and lower-cased, and any other value unchanged.
+func normalizeValue(name string, value any) any {
+	items, ok := value.([]any)
+	if !ok || !addressLists[name] {
+		return value
+	}
+	normalized := make([]string, len(items))
+	for i, item := range items {
+		normalized[i] = strings.ToLower(strings.TrimSpace(fmt.Sprint(item)))
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case json.Number:
+		f, err := v.Float64()
+		return err == nil && f == 0
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func sortedFields(fields map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,154 @@
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	migadu "github.com/z-xavier/migadu-go"
+)
+
+// Document is the desired state of an account. Only the domains it lists are
+// managed; within them, only the fields a resource sets are compared with the API,
+// so a document can pin a few settings and leave the rest to the admin panel.
+type Document struct {
+	Domains []Domain `json:"domains"`
+}
+
+// Domain is the desired state of a domain and of the resources in it.
+type Domain struct {
+	Name string `json:"name"`
+	// CreateDefaultAddresses lets Migadu add its default addresses to a new domain.
+	// It defaults to false: the document would not list those addresses, so a
+	// later run with Prune would delete them. It is never compared.
+	CreateDefaultAddresses *bool `json:"create_default_addresses,omitempty"`
+	migadu.UpdateDomainRequest
+	Mailboxes []Mailbox `json:"mailboxes,omitempty"`
+	Aliases   []Alias   `json:"aliases,omitempty"`
+	Rewrites  []Rewrite `json:"rewrites,omitempty"`
+}
+
+// Mailbox is the desired state of a mailbox. Password and PasswordMethod are only
+// used when the mailbox is created; passwords cannot be read back, so they are never compared.
+type Mailbox struct {
+	LocalPart      string                `json:"local_part"`
+	PasswordMethod migadu.PasswordMethod `json:"password_method,omitempty"`
+	migadu.UpdateMailboxRequest
+	Identities  []Identity   `json:"identities,omitempty"`
+	Forwardings []Forwarding `json:"forwardings,omitempty"`
+}
+
+// Identity is the desired state of an identity of a mailbox.
+type Identity struct {
+	LocalPart string `json:"local_part"`
+	migadu.UpdateIdentityRequest
+}
+
+// Forwarding is the desired state of an external forwarding of a mailbox.
+type Forwarding struct {
+	Address string `json:"address"`
+	migadu.UpdateForwardingRequest
+}
+
+// Alias is the desired state of an alias.
+type Alias struct {
+	LocalPart string `json:"local_part"`
+	migadu.UpdateAliasRequest
+}
+
+// Rewrite is the desired state of a rewrite. Rewrites are matched by name, so
+// renaming one in the document deletes and recreates it when pruning.
+type Rewrite struct {
+	Name string `json:"name"`
+	migadu.UpdateRewriteRequest
+}
+
+// DecodeDocument reads a JSON document. Unknown fields are rejected so that a
+// misspelled setting is not silently left unmanaged.
+func DecodeDocument(r io.Reader) (*Document, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	var doc Document
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Validate reports resources without a key and resources listed twice.
+// The settings themselves are validated when a plan is computed.
+func (d *Document) Validate() error {
+	domains := keys{kind: "domain"}
+	for _, domain := range d.Domains {
+		if err := domains.add(domain.Name); err != nil {
+			return err
+		}
+		mailboxes := keys{kind: "mailbox", scope: domain.Name}
+		aliases := keys{kind: "alias", scope: domain.Name}
+		rewrites := keys{kind: "rewrite", scope: domain.Name}
+		for _, mailbox := range domain.Mailboxes {
+			if err := mailboxes.add(mailbox.LocalPart); err != nil {
+				return err
+			}
+			scope := mailbox.LocalPart + "@" + domain.Name
+			identities := keys{kind: "identity", scope: scope}
+			forwardings := keys{kind: "forwarding", scope: scope}
+			for _, identity := range mailbox.Identities {
+				if err := identities.add(identity.LocalPart); err != nil {
+					return err
+				}
+			}
+			for _, forwarding := range mailbox.Forwardings {
+				if err := forwardings.add(forwarding.Address); err != nil {
+					return err
+				}
+			}
+		}
+		for _, alias := range domain.Aliases {
+			if err := aliases.add(alias.LocalPart); err != nil {
+				return err
+			}
+		}
+		for _, rewrite := range domain.Rewrites {
+			if err := rewrites.add(rewrite.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// keys detects duplicate keys of one kind of resource within a scope.
+type keys struct {
+	kind  string
+	scope string
+	seen  map[string]bool
+}
+
+func (k *keys) add(key string) error {
+	where := ""
+	if k.scope != "" {
+		where = " in " + k.scope
+	}
+	if strings.TrimSpace(key) == "" {
+		return fmt.Errorf("%s without a key%s", k.kind, where)
+	}
+	if k.seen == nil {
+		k.seen = map[string]bool{}
+	}
+	normalized := normalizeKey(key)
+	if k.seen[normalized] {
+		return fmt.Errorf("%s %q listed twice%s", k.kind, key, where)
+	}
+	k.seen[normalized] = true
+	return nil
+}
+
+// normalizeKey matches names, local parts and addresses case-insensitively, as the API does.
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(key), "."))
+}
@@ -0,0 +1,87 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	migadu "github.com/z-xavier/migadu-go"
+)
+
+// Live is the state of the domains of a document as read from the API.
+type Live struct {
+	Domains []*LiveDomain
+}
+
+// LiveDomain is an existing domain and the resources in it.
+type LiveDomain struct {
+	Domain    *migadu.Domain
+	Mailboxes []*LiveMailbox
+	Aliases   []*migadu.Alias
+	Rewrites  []*migadu.Rewrite
+}
+
+// LiveMailbox is an existing mailbox with its identities and forwardings.
+type LiveMailbox struct {
+	Mailbox     *migadu.Mailbox
+	Identities  []*migadu.Identity
+	Forwardings []*migadu.Forwarding
+}
+
+// Read lists the domains of doc that exist and everything in them.
+// Domains the document does not list are not read.
+func Read(ctx context.Context, client *migadu.Client, doc *Document) (*Live, error) {
+	domains, err := client.ListDomains(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list domains: %w", err)
+	}
+	wanted := map[string]bool{}
+	for _, domain := range doc.Domains {
+		wanted[normalizeKey(domain.Name)] = true
+	}
+	live := &Live{}
+	for _, domain := range domains {
+		if !wanted[normalizeKey(domain.Name)] {
+			continue
+		}
+		liveDomain, err := readDomain(ctx, client, domain)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", domain.Name, err)
+		}
+		live.Domains = append(live.Domains, liveDomain)
+	}
+	return live, nil
+}
+
+func readDomain(ctx context.Context, client *migadu.Client, domain *migadu.Domain) (*LiveDomain, error) {
+	live := &LiveDomain{Domain: domain}
+	mailboxes, err := client.ListMailboxes(ctx, domain.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, mailbox := range mailboxes {
+		liveMailbox := &LiveMailbox{Mailbox: mailbox}
+		if liveMailbox.Identities, err = client.ListIdentities(ctx, domain.Name, mailbox.LocalPart); err != nil {
+			return nil, fmt.Errorf("%s: %w", mailbox.LocalPart, err)
+		}
+		if liveMailbox.Forwardings, err = client.ListForwardings(ctx, domain.Name, mailbox.LocalPart); err != nil {
+			return nil, fmt.Errorf("%s: %w", mailbox.LocalPart, err)
+		}
+		live.Mailboxes = append(live.Mailboxes, liveMailbox)
+	}
+	if live.Aliases, err = client.ListAliases(ctx, domain.Name); err != nil {
+		return nil, err
+	}
+	if live.Rewrites, err = client.ListRewrites(ctx, domain.Name); err != nil {
+		return nil, err
+	}
+	return live, nil
+}
+
+func (l *Live) domain(name string) *LiveDomain {
+	for _, domain := range l.Domains {
+		if normalizeKey(domain.Domain.Name) == normalizeKey(name) {
+			return domain
+		}
+	}
+	return nil
+}
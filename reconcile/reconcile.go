@@ -0,0 +1,345 @@
+// Package reconcile converges a Migadu account to a desired-state document.
+//
+// Read fetches the live state of the domains a document lists, NewPlan compares
+// both and returns the create, update and delete operations with field-level
+// diffs, and Apply sends them through a migadu.Client in dependency order.
+// Domains are never deleted; with Options.Prune, resources in the listed domains
+// that the document does not mention are.
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	migadu "github.com/z-xavier/migadu-go"
+)
+
+// Action is what a Change does to a resource.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Kind is the type of resource a Change applies to.
+type Kind string
+
+const (
+	KindDomain     Kind = "domain"
+	KindMailbox    Kind = "mailbox"
+	KindIdentity   Kind = "identity"
+	KindForwarding Kind = "forwarding"
+	KindAlias      Kind = "alias"
+	KindRewrite    Kind = "rewrite"
+)
+
+// Change is one operation of a Plan.
+type Change struct {
+	Action Action
+	Kind   Kind
+	Domain string
+	// Mailbox is the local part of the mailbox an identity or forwarding belongs to.
+	Mailbox string
+	// Key identifies the resource: a domain name, local part, forwarding address or rewrite name.
+	Key   string
+	Diffs []FieldDiff
+	// Request is the request Apply sends, such as a migadu.CreateMailboxRequest
+	// or migadu.UpdateAliasRequest. It is nil for deletes.
+	Request any
+}
+
+// String describes the change, for example "update alias info@example.com".
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s %s", c.Action, c.Kind, c.name())
+}
+
+func (c Change) name() string {
+	switch c.Kind {
+	case KindDomain:
+		return c.Domain
+	case KindIdentity, KindForwarding:
+		return fmt.Sprintf("%s on %s@%s", c.address(), c.Mailbox, c.Domain)
+	case KindRewrite:
+		return fmt.Sprintf("%s on %s", c.Key, c.Domain)
+	}
+	return c.address()
+}
+
+func (c Change) address() string {
+	if strings.Contains(c.Key, "@") {
+		return c.Key
+	}
+	return c.Key + "@" + c.Domain
+}
+
+// Plan lists the changes that converge the live state to a document, in the order Apply makes them:
+// deletes first, from rewrites down to mailboxes, so a local part can move from an alias to a mailbox;
+// then domains, mailboxes, their identities and forwardings, and the aliases and rewrites that may target them.
+type Plan struct {
+	Changes []Change
+}
+
+// Empty reports whether the live state already matches the document.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String renders the plan with one line per change followed by its field diffs.
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, change := range p.Changes {
+		b.WriteString(change.String())
+		b.WriteByte('\n')
+		for _, diff := range change.Diffs {
+			if change.Action == ActionCreate {
+				fmt.Fprintf(&b, "    %s: %s\n", diff.Field, formatValue(diff.To))
+			} else {
+				fmt.Fprintf(&b, "    %s\n", diff)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Options controls how a plan is computed.
+type Options struct {
+	// Prune deletes mailboxes, identities, forwardings, aliases and rewrites in the
+	// document's domains that the document does not list. Without it they are left alone.
+	Prune bool
+}
+
+// phase orders changes for Apply.
+type phase int
+
+const (
+	deleteRewrites phase = iota
+	deleteAliases
+	deleteForwardings
+	deleteIdentities
+	deleteMailboxes
+	writeDomains
+	writeMailboxes
+	writeIdentities
+	writeForwardings
+	writeAliases
+	writeRewrites
+	phases
+)
+
+type planner struct {
+	opts    Options
+	changes [phases][]Change
+}
+
+// NewPlan compares doc with live. Every request of the plan is validated, so an
+// invalid document fails here rather than halfway through Apply.
+func NewPlan(doc *Document, live *Live, opts Options) (*Plan, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+	p := &planner{opts: opts}
+	for _, domain := range doc.Domains {
+		if err := p.domain(domain, live.domain(domain.Name)); err != nil {
+			return nil, err
+		}
+	}
+	plan := &Plan{}
+	for _, changes := range p.changes {
+		plan.Changes = append(plan.Changes, changes...)
+	}
+	return plan, nil
+}
+
+func (p *planner) domain(want Domain, live *LiveDomain) error {
+	change := Change{Kind: KindDomain, Domain: want.Name, Key: want.Name}
+	spec := want
+	spec.Mailboxes, spec.Aliases, spec.Rewrites = nil, nil, nil
+	if spec.CreateDefaultAddresses == nil {
+		spec.CreateDefaultAddresses = new(bool)
+	}
+	var existing any
+	if live != nil {
+		existing = live.Domain
+		change.Domain = live.Domain.Name
+	} else {
+		live = &LiveDomain{}
+	}
+	if err := planResource[migadu.CreateDomainRequest, migadu.UpdateDomainRequest](p, writeDomains, change, "name", spec, existing); err != nil {
+		return err
+	}
+	domain := change.Domain
+
+	listed := map[string]bool{}
+	for _, mailbox := range want.Mailboxes {
+		listed[normalizeKey(mailbox.LocalPart)] = true
+		var liveMailbox *LiveMailbox
+		for _, candidate := range live.Mailboxes {
+			if normalizeKey(candidate.Mailbox.LocalPart) == normalizeKey(mailbox.LocalPart) {
+				liveMailbox = candidate
+			}
+		}
+		if err := p.mailbox(domain, mailbox, liveMailbox); err != nil {
+			return err
+		}
+	}
+	for _, mailbox := range live.Mailboxes {
+		if !listed[normalizeKey(mailbox.Mailbox.LocalPart)] {
+			p.prune(deleteMailboxes, Change{Kind: KindMailbox, Domain: domain, Key: mailbox.Mailbox.LocalPart})
+		}
+	}
+
+	aliases := make([]keyed, len(live.Aliases))
+	for i, alias := range live.Aliases {
+		aliases[i] = keyed{alias.LocalPart, alias}
+	}
+	for _, alias := range want.Aliases {
+		change := Change{Kind: KindAlias, Domain: domain, Key: alias.LocalPart}
+		if err := planResource[migadu.CreateAliasRequest, migadu.UpdateAliasRequest](p, writeAliases, change, "local_part", alias, find(aliases, alias.LocalPart)); err != nil {
+			return err
+		}
+	}
+	p.pruneUnlisted(deleteAliases, Change{Kind: KindAlias, Domain: domain}, aliases, keysOf(want.Aliases, func(a Alias) string { return a.LocalPart }))
+
+	rewrites := make([]keyed, len(live.Rewrites))
+	for i, rewrite := range live.Rewrites {
+		rewrites[i] = keyed{rewrite.Name, rewrite}
+	}
+	for _, rewrite := range want.Rewrites {
+		change := Change{Kind: KindRewrite, Domain: domain, Key: rewrite.Name}
+		if err := planResource[migadu.CreateRewriteRequest, migadu.UpdateRewriteRequest](p, writeRewrites, change, "name", rewrite, find(rewrites, rewrite.Name)); err != nil {
+			return err
+		}
+	}
+	p.pruneUnlisted(deleteRewrites, Change{Kind: KindRewrite, Domain: domain}, rewrites, keysOf(want.Rewrites, func(r Rewrite) string { return r.Name }))
+	return nil
+}
+
+func (p *planner) mailbox(domain string, want Mailbox, live *LiveMailbox) error {
+	change := Change{Kind: KindMailbox, Domain: domain, Key: want.LocalPart}
+	spec := want
+	spec.Identities, spec.Forwardings = nil, nil
+	var existing any
+	if live != nil {
+		existing = live.Mailbox
+	} else {
+		live = &LiveMailbox{}
+	}
+	if err := planResource[migadu.CreateMailboxRequest, migadu.UpdateMailboxRequest](p, writeMailboxes, change, "local_part", spec, existing); err != nil {
+		return err
+	}
+
+	identities := make([]keyed, len(live.Identities))
+	for i, identity := range live.Identities {
+		identities[i] = keyed{identity.LocalPart, identity}
+	}
+	for _, identity := range want.Identities {
+		change := Change{Kind: KindIdentity, Domain: domain, Mailbox: want.LocalPart, Key: identity.LocalPart}
+		if err := planResource[migadu.CreateIdentityRequest, migadu.UpdateIdentityRequest](p, writeIdentities, change, "local_part", identity, find(identities, identity.LocalPart)); err != nil {
+			return err
+		}
+	}
+	p.pruneUnlisted(deleteIdentities, Change{Kind: KindIdentity, Domain: domain, Mailbox: want.LocalPart}, identities, keysOf(want.Identities, func(i Identity) string { return i.LocalPart }))
+
+	forwardings := make([]keyed, len(live.Forwardings))
+	for i, forwarding := range live.Forwardings {
+		forwardings[i] = keyed{forwarding.Address, forwarding}
+	}
+	for _, forwarding := range want.Forwardings {
+		change := Change{Kind: KindForwarding, Domain: domain, Mailbox: want.LocalPart, Key: forwarding.Address}
+		if err := planResource[migadu.CreateForwardingRequest, migadu.UpdateForwardingRequest](p, writeForwardings, change, "address", forwarding, find(forwardings, forwarding.Address)); err != nil {
+			return err
+		}
+	}
+	p.pruneUnlisted(deleteForwardings, Change{Kind: KindForwarding, Domain: domain, Mailbox: want.LocalPart}, forwardings, keysOf(want.Forwardings, func(f Forwarding) string { return f.Address }))
+	return nil
+}
+
+// keyed is a live resource with the key it is matched by.
+type keyed struct {
+	key   string
+	value any
+}
+
+// find returns the live resource matching key, or nil.
+func find(resources []keyed, key string) any {
+	for _, resource := range resources {
+		if normalizeKey(resource.key) == normalizeKey(key) {
+			return resource.value
+		}
+	}
+	return nil
+}
+
+func keysOf[T any](items []T, key func(T) string) map[string]bool {
+	result := make(map[string]bool, len(items))
+	for _, item := range items {
+		result[normalizeKey(key(item))] = true
+	}
+	return result
+}
+
+// pruneUnlisted deletes the live resources whose key is not listed.
+func (p *planner) pruneUnlisted(phase phase, change Change, live []keyed, listed map[string]bool) {
+	for _, resource := range live {
+		if !listed[normalizeKey(resource.key)] {
+			change.Key = resource.key
+			p.prune(phase, change)
+		}
+	}
+}
+
+func (p *planner) prune(phase phase, change Change) {
+	if p.opts.Prune {
+		change.Action = ActionDelete
+		p.changes[phase] = append(p.changes[phase], change)
+	}
+}
+
+// planResource adds a create of desired when live is nil, and otherwise an update of the
+// fields that differ. Fields a create request does not accept are set by an update right after it.
+func planResource[C, U interface{ Validate() error }](p *planner, phase phase, change Change, key string, desired, live any) error {
+	want, err := fields(desired)
+	if err != nil {
+		return fmt.Errorf("%s: %w", change.String(), err)
+	}
+	var changed map[string]json.RawMessage
+	if live == nil {
+		accepted, rest := split(want, reflect.TypeFor[C]())
+		change.Action = ActionCreate
+		change.Diffs = created(accepted, key)
+		if err = addChange(p, phase, change, convert[C], accepted); err != nil {
+			return err
+		}
+		changed = rest
+		change.Diffs = created(rest, key)
+	} else {
+		have, err := fields(live)
+		if err != nil {
+			return fmt.Errorf("%s: %w", change.String(), err)
+		}
+		change.Diffs, changed = compare(want, have, key)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	change.Action = ActionUpdate
+	return addChange(p, phase, change, convert[U], changed)
+}
+
+// addChange builds the request of change from fields and validates it.
+func addChange[R interface{ Validate() error }](p *planner, phase phase, change Change, build func(map[string]json.RawMessage) (R, error), fields map[string]json.RawMessage) error {
+	request, err := build(fields)
+	if err == nil {
+		err = request.Validate()
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", change, err)
+	}
+	change.Request = request
+	p.changes[phase] = append(p.changes[phase], change)
+	return nil
+}
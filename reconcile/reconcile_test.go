@@ -0,0 +1,208 @@
+package reconcile_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	migadu "github.com/z-xavier/migadu-go"
+	"github.com/z-xavier/migadu-go/migadutest"
+	"github.com/z-xavier/migadu-go/reconcile"
+)
+
+const document = `{
+	"domains": [
+		{
+			"name": "example.com",
+			"description": "Main",
+			"mailboxes": [
+				{"local_part": "demo", "name": "Demo Person"},
+				{"local_part": "team", "name": "Team", "password": "secret", "remove_upon_expiry": true,
+				 "identities": [{"local_part": "support", "name": "Support"}]}
+			],
+			"aliases": [{"local_part": "info", "destinations": ["team@example.com"]}]
+		},
+		{
+			"name": "new.example",
+			"aliases": [{"local_part": "hello", "destinations": ["demo@example.com"]}]
+		}
+	]
+}`
+
+func newAccount(t *testing.T) (*migadutest.Server, *migadu.Client) {
+	t.Helper()
+	s := migadutest.NewUnstartedServer()
+	s.AddDomain(migadu.Domain{Name: "example.com"})
+	s.AddMailbox("example.com", migadu.Mailbox{LocalPart: "demo", Name: "Demo", MaySend: true})
+	s.AddIdentity("example.com", "demo", migadu.Identity{LocalPart: "sales", Name: "Sales"})
+	s.AddAlias("example.com", migadu.Alias{LocalPart: "info", Destinations: []string{"old@example.com"}})
+	s.AddAlias("example.com", migadu.Alias{LocalPart: "stale", Destinations: []string{"demo@example.com"}})
+	s.AddRewrite("example.com", migadu.Rewrite{Name: "old", LocalPartRule: "old-*", Destinations: []string{"demo@example.com"}})
+	s.AddDomain(migadu.Domain{Name: "unmanaged.example"})
+	s.AddAlias("unmanaged.example", migadu.Alias{LocalPart: "keep", Destinations: []string{"demo@example.com"}})
+	client, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, client
+}
+
+func plan(t *testing.T, client *migadu.Client, opts reconcile.Options) (*reconcile.Document, *reconcile.Plan) {
+	t.Helper()
+	doc, err := reconcile.DecodeDocument(strings.NewReader(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	live, err := reconcile.Read(context.Background(), client, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := reconcile.NewPlan(doc, live, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc, p
+}
+
+func changes(p *reconcile.Plan) []string {
+	result := make([]string, len(p.Changes))
+	for i, change := range p.Changes {
+		result[i] = change.String()
+	}
+	return result
+}
+
+func TestPlanOrdersChangesByDependency(t *testing.T) {
+	_, client := newAccount(t)
+	_, p := plan(t, client, reconcile.Options{})
+	want := []string{
+		"update domain example.com",
+		"create domain new.example",
+		"update mailbox demo@example.com",
+		"create mailbox team@example.com",
+		"update mailbox team@example.com",
+		"create identity support@example.com on team@example.com",
+		"update alias info@example.com",
+		"create alias hello@new.example",
+	}
+	if got := changes(p); !reflect.DeepEqual(got, want) {
+		t.Fatalf("changes = %q", got)
+	}
+	update := p.Changes[2]
+	if !reflect.DeepEqual(update.Diffs, []reconcile.FieldDiff{{Field: "name", From: "Demo", To: "Demo Person"}}) {
+		t.Fatalf("diffs = %+v", update.Diffs)
+	}
+	if request, ok := update.Request.(migadu.UpdateMailboxRequest); !ok || *request.Name != "Demo Person" || request.MaySend != nil {
+		t.Fatalf("request = %#v", update.Request)
+	}
+	if create, ok := p.Changes[1].Request.(migadu.CreateDomainRequest); !ok || create.CreateDefaultAddresses == nil || *create.CreateDefaultAddresses {
+		t.Fatalf("create domain request = %#v", p.Changes[1].Request)
+	}
+	if create, ok := p.Changes[3].Request.(migadu.CreateMailboxRequest); !ok || create.Password != "secret" {
+		t.Fatalf("create request = %#v", p.Changes[3].Request)
+	}
+	if followUp, ok := p.Changes[4].Request.(migadu.UpdateMailboxRequest); !ok || followUp.RemoveUponExpiry == nil || followUp.Name != nil {
+		t.Fatalf("follow-up request = %#v", p.Changes[4].Request)
+	}
+	if text := p.String(); strings.Contains(text, "secret") || !strings.Contains(text, `    destinations: ["old@example.com"] -> ["team@example.com"]`) {
+		t.Fatalf("String() =\n%s", text)
+	}
+}
+
+func TestAddressListsIgnoreCaseAndOrder(t *testing.T) {
+	s := migadutest.NewUnstartedServer()
+	s.AddDomain(migadu.Domain{Name: "example.com", CatchallDestinations: []string{"Team@example.com", "ops@example.com"}})
+	s.AddAlias("example.com", migadu.Alias{LocalPart: "info", Destinations: []string{"b@example.com", "A@Example.com"}})
+	client, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := reconcile.DecodeDocument(strings.NewReader(`{"domains":[{"name":"example.com",
+		"catchall_destinations":["ops@example.com","team@EXAMPLE.com"],
+		"aliases":[{"local_part":"info","destinations":["a@example.com","b@example.com"]}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	live, err := reconcile.Read(context.Background(), client, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := reconcile.NewPlan(doc, live, reconcile.Options{Prune: true})
+	if err != nil || !p.Empty() {
+		t.Fatalf("plan = %q, %v", changes(p), err)
+	}
+}
+
+func TestPlanPrunesOnlyWhenAsked(t *testing.T) {
+	_, client := newAccount(t)
+	_, p := plan(t, client, reconcile.Options{Prune: true})
+	got := changes(p)
+	want := []string{
+		"delete rewrite old on example.com",
+		"delete alias stale@example.com",
+		"delete identity sales@example.com on demo@example.com",
+	}
+	if !reflect.DeepEqual(got[:3], want) {
+		t.Fatalf("changes = %q", got)
+	}
+	for _, change := range got[3:] {
+		if strings.HasPrefix(change, "delete") || strings.Contains(change, "unmanaged.example") {
+			t.Fatalf("changes = %q", got)
+		}
+	}
+}
+
+func TestApplyConverges(t *testing.T) {
+	s, client := newAccount(t)
+	ctx := context.Background()
+	doc, p := plan(t, client, reconcile.Options{Prune: true})
+	if err := reconcile.Apply(ctx, client, p); err != nil {
+		t.Fatal(err)
+	}
+	if mailbox, ok := s.Mailbox("example.com", "team"); !ok || !mailbox.RemoveUponExpiry {
+		t.Fatalf("team mailbox = %+v, %v", mailbox, ok)
+	}
+	live, err := reconcile.Read(ctx, client, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := reconcile.NewPlan(doc, live, reconcile.Options{Prune: true})
+	if err != nil || !again.Empty() {
+		t.Fatalf("second plan = %v, %v", changes(again), err)
+	}
+	if aliases, err := client.ListAliases(ctx, "unmanaged.example"); err != nil || len(aliases) != 1 {
+		t.Fatalf("unmanaged aliases = %v, %v", aliases, err)
+	}
+}
+
+func TestApplyStopsAtTheFirstError(t *testing.T) {
+	s, client := newAccount(t)
+	_, p := plan(t, client, reconcile.Options{})
+	s.AddFault(migadutest.Fault{Match: migadutest.MatchRoute(http.MethodPut, "/v1/domains/example.com/mailboxes/demo"), Status: http.StatusInternalServerError})
+	err := reconcile.Apply(context.Background(), client.With(migadu.WithRetryPolicy(nil)), p)
+	var applyErr *reconcile.ApplyError
+	if !errors.As(err, &applyErr) || applyErr.Applied != 2 || applyErr.Change.Key != "demo" || !migadu.IsServer(err) {
+		t.Fatalf("Apply() error = %v", err)
+	}
+}
+
+func TestInvalidDocumentsAreRejected(t *testing.T) {
+	for name, doc := range map[string]string{
+		"unknown field": `{"domains":[{"name":"example.com","descripton":"typo"}]}`,
+		"duplicate":     `{"domains":[{"name":"example.com","aliases":[{"local_part":"info"},{"local_part":"INFO"}]}]}`,
+	} {
+		if _, err := reconcile.DecodeDocument(strings.NewReader(doc)); err == nil {
+			t.Fatalf("%s: DecodeDocument() succeeded", name)
+		}
+	}
+	doc, err := reconcile.DecodeDocument(strings.NewReader(`{"domains":[{"name":"example.com","aliases":[{"local_part":"info"}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = reconcile.NewPlan(doc, &reconcile.Live{}, reconcile.Options{}); !migadu.IsValidation(err) || !strings.Contains(err.Error(), "create alias info@example.com") {
+		t.Fatalf("NewPlan() error = %v", err)
+	}
+}
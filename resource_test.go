@@ -51,8 +51,8 @@ func TestDocumentedEndpoints(t *testing.T) {
 			_, err := c.GetMailbox(ctx, "example.com", "demo")
 			return err
 		}},
-		{name: "mailboxes create", method: "POST", path: "/v1/domains/example.com/mailboxes", response: `{}`, expectedJSON: map[string]any{"local_part": "demo", "forwarding_to": "outside@example.net", "wildcard_sender": false}, call: func(ctx context.Context, c *Client) error {
-			_, err := c.CreateMailbox(ctx, "example.com", CreateMailboxRequest{LocalPart: "demo", ForwardingTo: "outside@example.net", WildcardSender: &falseValue})
+		{name: "mailboxes create", method: "POST", path: "/v1/domains/example.com/mailboxes", response: `{}`, expectedJSON: map[string]any{"local_part": "demo", "name": "Demo", "password": "secret", "forwarding_to": "outside@example.net", "wildcard_sender": false}, call: func(ctx context.Context, c *Client) error {
+			_, err := c.CreateMailbox(ctx, "example.com", CreateMailboxRequest{LocalPart: "demo", Name: "Demo", Password: "secret", ForwardingTo: "outside@example.net", WildcardSender: &falseValue})
 			return err
 		}},
 		{name: "mailboxes update", method: "PUT", path: "/v1/domains/example.com/mailboxes/demo", response: `{}`, expectedJSON: map[string]any{"may_send": false, "recipient_denylist": []any{}}, call: func(ctx context.Context, c *Client) error {
@@ -111,7 +111,8 @@ func TestDocumentedEndpoints(t *testing.T) {
 			return err
 		}},
 		{name: "aliases update", method: "PUT", path: "/v1/domains/example.com/aliases/demo", response: `{}`, expectedJSON: map[string]any{"destinations": []any{}}, call: func(ctx context.Context, c *Client) error {
-			_, err := c.UpdateAlias(ctx, "example.com", "demo", UpdateAliasRequest{Destinations: &emptyList})
+			// Validation rejects an empty list, so skip it to check that the list is still sent explicitly.
+			_, err := c.With(WithoutValidation()).UpdateAlias(ctx, "example.com", "demo", UpdateAliasRequest{Destinations: &emptyList})
 			return err
 		}},
 		{name: "aliases delete", method: "DELETE", path: "/v1/domains/example.com/aliases/demo", response: ``, call: func(ctx context.Context, c *Client) error { return c.DeleteAlias(ctx, "example.com", "demo") }},
@@ -178,7 +179,7 @@ func TestDomainFlexibleResponseFields(t *testing.T) {
 	if domain.Name != "example.com" || !reflect.DeepEqual(domain.Tags, []string{"work", "business"}) {
 		t.Fatalf("Domain = %+v", domain)
 	}
-	if domain.SpamAggressiveness != "0" {
+	if domain.SpamAggressiveness != SpamAggressivenessDefault {
 		t.Fatalf("SpamAggressiveness = %q", domain.SpamAggressiveness)
 	}
 }
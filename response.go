@@ -0,0 +1,150 @@
+package migadu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds the limit set with WithMaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// responseReader enforces the body size limit and remembers transport errors,
+// so a truncated connection can be told apart from a body that is not valid JSON.
+type responseReader struct {
+	r     io.Reader
+	limit int64
+	read  int64
+	err   error
+}
+
+func newResponseReader(r io.Reader, limit int64) *responseReader {
+	if limit > 0 {
+		// One extra byte tells a body of exactly limit bytes from a larger one.
+		r = io.LimitReader(r, limit+1)
+	}
+	return &responseReader{r: r, limit: limit}
+}
+
+func (r *responseReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+	if r.limit > 0 && r.read > r.limit {
+		r.err = fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, r.limit)
+		return n, r.err
+	}
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// decodeError reports a response body that was read completely but could not be decoded.
+// Unlike transport errors it is never retried.
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.err
+}
+
+// decodeListJSON decodes the array under key of a JSON object one element at a time,
+// so only a single element is buffered next to the result. Other members are skipped.
+func decodeListJSON[T any](body *responseReader, key string) ([]*T, error) {
+	items, err := decodeList[T](json.NewDecoder(body), key)
+	switch {
+	case body.err != nil:
+		return nil, body.err
+	case err == io.EOF:
+		return nil, nil
+	case err != nil:
+		return nil, &decodeError{err: err}
+	}
+	return items, nil
+}
+
+func decodeList[T any](dec *json.Decoder, key string) ([]*T, error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+	var items []*T
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if name, _ := token.(string); !strings.EqualFold(name, key) {
+			var skipped json.RawMessage
+			if err = dec.Decode(&skipped); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if token, err = dec.Token(); err != nil {
+			return nil, err
+		}
+		if token == nil {
+			continue
+		}
+		if token != json.Delim('[') {
+			return nil, fmt.Errorf("json: %s is %v, want an array", key, token)
+		}
+		for dec.More() {
+			var item *T
+			if err = dec.Decode(&item); err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if err = expectDelim(dec, ']'); err != nil {
+			return nil, err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("json: unexpected %v, want %v", token, delim)
+	}
+	return nil
+}
+
+// decodeJSON decodes the first JSON value of body into v. An empty body leaves v untouched.
+func decodeJSON(body *responseReader, v any) error {
+	err := json.NewDecoder(body).Decode(v)
+	switch {
+	case body.err != nil:
+		return body.err
+	case err == io.EOF:
+		return nil
+	case err != nil:
+		return &decodeError{err: err}
+	}
+	return nil
+}
+
+// WithMaxResponseBytes fails calls whose response body exceeds n bytes with ErrResponseTooLarge.
+// Zero, the default, disables the limit.
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *Client) {
+		c.maxResponseBytes = n
+	}
+}
@@ -0,0 +1,171 @@
+package migadu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMaxResponseBytes(t *testing.T) {
+	body := `{"mailboxes":[{"local_part":"one"},{"local_part":"two"}]}`
+	calls := 0
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithRetryPolicy(&RetryPolicy{MaxAttempts: 3}),
+		WithMaxResponseBytes(int64(len(body)-1)),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.ListMailboxes(context.Background(), "example.com"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("ListMailboxes() error = %v, want ErrResponseTooLarge", err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+	mailboxes, err := client.With(WithMaxResponseBytes(int64(len(body)))).ListMailboxes(context.Background(), "example.com")
+	if err != nil || len(mailboxes) != 2 {
+		t.Fatalf("ListMailboxes() at limit = %v, %v", mailboxes, err)
+	}
+}
+
+func TestStreamingDecodeRetriesTruncatedBodyOnly(t *testing.T) {
+	responses := []io.Reader{
+		io.MultiReader(strings.NewReader(`{"mailboxes":[{"local_`), errReader{io.ErrUnexpectedEOF}),
+		strings.NewReader(`{"mailboxes":[{"local_part":"one"}]}`),
+		strings.NewReader(`{"mailboxes":[`),
+	}
+	calls := 0
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithRetryPolicy(&RetryPolicy{MaxAttempts: 2}),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			body := responses[calls]
+			calls++
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body)}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mailboxes, err := client.ListMailboxes(context.Background(), "example.com")
+	if err != nil || len(mailboxes) != 1 || mailboxes[0].LocalPart != "one" || calls != 2 {
+		t.Fatalf("ListMailboxes() = %v, %v after %d calls", mailboxes, err, calls)
+	}
+	// A body that ends cleanly but is not valid JSON is a decode error and is not retried.
+	if _, err = client.ListMailboxes(context.Background(), "example.com"); err == nil || calls != 3 {
+		t.Fatalf("ListMailboxes() error = %v after %d calls", err, calls)
+	}
+}
+
+func TestErrorBodyIsKeptWhole(t *testing.T) {
+	body := `{"error":"invalid","errors":{"name":["is required"]},"padding":"` + strings.Repeat("x", 10000) + `"}`
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusUnprocessableEntity, Body: io.NopCloser(strings.NewReader(body))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.ListMailboxes(context.Background(), "example.com")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Body != body || len(apiErr.FieldErrors) != 1 {
+		t.Fatalf("ListMailboxes() error = %v", err)
+	}
+}
+
+func syntheticMailboxList(n int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`{"mailboxes":[`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `{"local_part":"user%d","domain_name":"example.com","address":"user%d@example.com","name":"User %d","is_internal":false,"may_send":true,"may_receive":true,"may_access_imap":true,"may_access_pop3":true,"may_access_managesieve":true,"spam_action":"folder","spam_aggressiveness":"default","sender_denylist":[],"sender_allowlist":[],"recipient_denylist":[],"autorespond_active":false,"autorespond_subject":"","autorespond_body":"","footer_active":false,"footer_plain_body":"","footer_html_body":""}`, i, i, i)
+	}
+	buf.WriteString(`]}`)
+	return buf.Bytes()
+}
+
+// BenchmarkListMailboxes decodes a large mailbox list straight from the response body.
+func BenchmarkListMailboxes(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		body := syntheticMailboxList(n)
+		client, err := NewWithOptions("admin@example.com", "secret",
+			WithBaseURL("https://api.test"),
+			WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body))}, nil
+			})),
+		)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("mailboxes=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+			for i := 0; i < b.N; i++ {
+				if _, err := client.ListMailboxes(context.Background(), "example.com"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkReadAllUnmarshal is the previous response path, buffering the body before decoding it.
+func BenchmarkReadAllUnmarshal(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		body := syntheticMailboxList(n)
+		b.Run(fmt.Sprintf("mailboxes=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+			for i := 0; i < b.N; i++ {
+				data, err := io.ReadAll(io.NopCloser(bytes.NewReader(body)))
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(strings.TrimSpace(string(data))) == 0 {
+					b.Fatal("empty body")
+				}
+				var result struct {
+					Mailboxes []*Mailbox `json:"mailboxes"`
+				}
+				if err = json.Unmarshal(data, &result); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeListJSON(t *testing.T) {
+	tests := []struct {
+		body    string
+		want    int
+		wantErr bool
+	}{
+		{body: ``, want: 0},
+		{body: `{"mailboxes":null}`, want: 0},
+		{body: `{"total":2,"meta":{"page":[1]},"Mailboxes":[{"local_part":"a"},null]}`, want: 2},
+		{body: `{"mailboxes":{}}`, wantErr: true},
+		{body: `[]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		items, err := decodeListJSON[Mailbox](newResponseReader(strings.NewReader(tt.body), 0), "mailboxes")
+		if (err != nil) != tt.wantErr || len(items) != tt.want {
+			t.Errorf("decodeListJSON(%s) = %d items, %v", tt.body, len(items), err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package migadu
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ResponseInfo holds the metadata of the last attempt of a call.
+// Attach one to a context with CaptureResponse; it works with every resource method.
+type ResponseInfo struct {
+	// StatusCode, Header and Body are empty when the last attempt failed without a response.
+	StatusCode int
+	Header     http.Header
+	// Latency covers the last attempt, from sending the request until its body was read.
+	Latency time.Duration
+	// Body is the raw response body, before decoding.
+	Body []byte
+	// Attempts counts the requests sent for the call, including retries and
+	// attempts that failed without a response.
+	Attempts int
+}
+
+type responseInfoKey struct{}
+
+// CaptureResponse returns a context that makes calls store their response metadata in info.
+// The info is overwritten by every call made with the context, so use one per call:
+//
+//	var info migadu.ResponseInfo
+//	mailbox, err := client.CreateMailbox(migadu.CaptureResponse(ctx, &info), "example.com", req)
+//	fmt.Println(info.StatusCode, info.Header.Get("X-Request-Id"))
+func CaptureResponse(ctx context.Context, info *ResponseInfo) context.Context {
+	return context.WithValue(ctx, responseInfoKey{}, info)
+}
+
+func responseInfoFrom(ctx context.Context) *ResponseInfo {
+	info, _ := ctx.Value(responseInfoKey{}).(*ResponseInfo)
+	return info
+}
+
+// captureBody tees body into a buffer stored on info once the attempt is done.
+func (info *ResponseInfo) captureBody(resp *http.Response, started time.Time) (io.Reader, func()) {
+	info.StatusCode = resp.StatusCode
+	info.Header = resp.Header.Clone()
+	info.Body = nil
+	var buf bytes.Buffer
+	return io.TeeReader(resp.Body, &buf), func() {
+		info.Body = buf.Bytes()
+		info.Latency = time.Since(started)
+	}
+}
+
+// captureFailure clears the response of an earlier attempt after an attempt that got none.
+func (info *ResponseInfo) captureFailure(started time.Time) {
+	info.StatusCode = 0
+	info.Header = nil
+	info.Body = nil
+	info.Latency = time.Since(started)
+}
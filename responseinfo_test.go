@@ -0,0 +1,96 @@
+package migadu
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCaptureResponse(t *testing.T) {
+	statuses := []int{http.StatusServiceUnavailable, http.StatusCreated}
+	calls := 0
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithRetryPolicy(&RetryPolicy{MaxAttempts: 2}),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			status := statuses[calls]
+			calls++
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{"X-Request-Id": []string{"req-1"}, "Retry-After": []string{"0"}},
+				Body:       io.NopCloser(strings.NewReader(`{"name":"example.com"} `)),
+			}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info ResponseInfo
+	domain, err := client.GetDomain(CaptureResponse(context.Background(), &info), "example.com")
+	if err != nil || domain.Name != "example.com" {
+		t.Fatalf("GetDomain() = %+v, %v", domain, err)
+	}
+	if info.StatusCode != http.StatusCreated || info.Attempts != 2 || info.Header.Get("X-Request-Id") != "req-1" {
+		t.Fatalf("info = %+v", info)
+	}
+	if string(info.Body) != `{"name":"example.com"} ` || info.Latency <= 0 {
+		t.Fatalf("info body = %q, latency = %v", info.Body, info.Latency)
+	}
+}
+
+func TestCaptureResponseOnError(t *testing.T) {
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{"error":"not_found"}`))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info ResponseInfo
+	if _, err = client.ListAliases(CaptureResponse(context.Background(), &info), "example.com"); !IsNotFound(err) {
+		t.Fatalf("ListAliases() error = %v", err)
+	}
+	if info.StatusCode != http.StatusNotFound || string(info.Body) != `{"error":"not_found"}` {
+		t.Fatalf("info = %+v", info)
+	}
+}
+
+func TestCaptureResponseCountsTransportFailures(t *testing.T) {
+	attempts := 0
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithRetryPolicy(&RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			if attempts++; attempts == 2 {
+				return &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"X-Request-Id": {"req-2"}}, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+			}
+			return nil, errors.New("connection reset")
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info ResponseInfo
+	ctx := CaptureResponse(context.Background(), &info)
+	if _, err = client.ListAliases(ctx, "example.com"); err == nil {
+		t.Fatal("ListAliases() succeeded")
+	}
+	// The last attempt failed without a response, so the 503 of the second one is not reported.
+	if info.Attempts != 3 || info.StatusCode != 0 || info.Header != nil || info.Body != nil {
+		t.Fatalf("info = %+v", info)
+	}
+
+	// The same info is reset by the next call.
+	if _, err = client.ListAliases(ctx, "example.com"); err == nil {
+		t.Fatal("ListAliases() succeeded")
+	}
+	if info.Attempts != 3 {
+		t.Fatalf("second call info = %+v", info)
+	}
+}
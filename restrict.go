@@ -0,0 +1,96 @@
+package migadu
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Errors wrapped by RestrictionError.
+var (
+	ErrReadOnly         = errors.New("client is read-only")
+	ErrDomainNotAllowed = errors.New("domain is not allowed for this client")
+)
+
+// RestrictionError reports a call rejected by a restricted client before it reached the network.
+// It matches ErrReadOnly or ErrDomainNotAllowed through errors.Is.
+type RestrictionError struct {
+	Method string
+	Domain string
+	Err    error
+}
+
+func (e *RestrictionError) Error() string {
+	switch {
+	case e.Domain != "":
+		return fmt.Sprintf("%s: %v", e.Domain, e.Err)
+	case e.Method != "":
+		return fmt.Sprintf("%s: %v", e.Method, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *RestrictionError) Unwrap() error {
+	return e.Err
+}
+
+// ReadOnly returns a copy of c that rejects every call that changes the account with
+// ErrReadOnly: every non-GET call and domain activation, whether typed or made through Do.
+// Clients derived from it with With stay read-only.
+func (c *Client) ReadOnly() *Client {
+	clone := c.Clone()
+	clone.readOnly = true
+	return clone
+}
+
+// RestrictToDomains returns a copy of c that only operates on the given domains.
+// Domain-scoped calls for other domains fail with ErrDomainNotAllowed, CreateDomain
+// and Do on the domain list are rejected, and ListDomains only returns allowed
+// domains. Restricting an already restricted client keeps only the domains allowed by both.
+func (c *Client) RestrictToDomains(domains ...string) *Client {
+	allowed := make(map[string]struct{}, len(domains))
+	for _, domain := range domains {
+		key := normalizeDomain(domain)
+		if _, ok := c.allowedDomains[key]; c.allowedDomains == nil || ok {
+			allowed[key] = struct{}{}
+		}
+	}
+	clone := c.Clone()
+	clone.allowedDomains = allowed
+	return clone
+}
+
+func normalizeDomain(domain string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+}
+
+func (c *Client) checkDomain(domain string) error {
+	if c.allowedDomains == nil {
+		return nil
+	}
+	if _, ok := c.allowedDomains[normalizeDomain(domain)]; ok {
+		return nil
+	}
+	return &RestrictionError{Domain: domain, Err: ErrDomainNotAllowed}
+}
+
+func (c *Client) checkReadOnly(req *http.Request) error {
+	if c.readOnly && isMutation(req) {
+		return &RestrictionError{Method: req.Method, Err: ErrReadOnly}
+	}
+	return nil
+}
+
+func (c *Client) filterDomains(domains []*Domain) []*Domain {
+	if c.allowedDomains == nil {
+		return domains
+	}
+	filtered := make([]*Domain, 0, len(domains))
+	for _, domain := range domains {
+		if _, ok := c.allowedDomains[normalizeDomain(domain.Name)]; ok {
+			filtered = append(filtered, domain)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,92 @@
+package migadu
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newRestrictTestClient(t *testing.T, sent *[]string) *Client {
+	t.Helper()
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			*sent = append(*sent, req.Method+" "+req.URL.Path)
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"domains":[{"name":"example.com"},{"name":"Other.example"}]}`))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestReadOnlyRejectsMutations(t *testing.T) {
+	var sent []string
+	client := newRestrictTestClient(t, &sent).ReadOnly()
+	ctx := context.Background()
+	if _, err := client.ListDomains(ctx); err != nil {
+		t.Fatal(err)
+	}
+	err := client.DeleteAlias(ctx, "example.com", "info")
+	var restrictionErr *RestrictionError
+	if !errors.Is(err, ErrReadOnly) || !errors.As(err, &restrictionErr) || restrictionErr.Method != http.MethodDelete {
+		t.Fatalf("DeleteAlias() error = %v", err)
+	}
+	if _, err = client.With(WithTimeout(0)).CreateDomain(ctx, CreateDomainRequest{Name: "new.example"}); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("CreateDomain() on derived client error = %v", err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("sent = %q", sent)
+	}
+}
+
+func TestReadOnlyRejectsActivation(t *testing.T) {
+	var sent []string
+	client := newRestrictTestClient(t, &sent).ReadOnly()
+	_, err := client.ActivateDomain(context.Background(), "example.com")
+	var restrictionErr *RestrictionError
+	if !errors.Is(err, ErrReadOnly) || !errors.As(err, &restrictionErr) || restrictionErr.Method != http.MethodGet {
+		t.Fatalf("ActivateDomain() error = %v", err)
+	}
+	err = client.Do(context.Background(), http.MethodGet, []string{"domains", "example.com", "Activate"}, nil, nil)
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("Do() activate error = %v", err)
+	}
+	if len(sent) != 0 {
+		t.Fatalf("sent = %q", sent)
+	}
+	if err = client.Do(context.Background(), http.MethodGet, []string{"domains", "example.com", "mailboxes"}, nil, nil); err != nil || len(sent) != 1 {
+		t.Fatalf("Do() read = %v, sent %q", err, sent)
+	}
+}
+
+func TestRestrictToDomains(t *testing.T) {
+	var sent []string
+	client := newRestrictTestClient(t, &sent).RestrictToDomains("Example.com.")
+	ctx := context.Background()
+	domains, err := client.ListDomains(ctx)
+	if err != nil || len(domains) != 1 || domains[0].Name != "example.com" {
+		t.Fatalf("ListDomains() = %+v, %v", domains, err)
+	}
+	if _, err = client.ListMailboxes(ctx, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetMailbox(ctx, "other.example", "demo")
+	var restrictionErr *RestrictionError
+	if !errors.Is(err, ErrDomainNotAllowed) || !errors.As(err, &restrictionErr) || restrictionErr.Domain != "other.example" {
+		t.Fatalf("GetMailbox() error = %v", err)
+	}
+	if _, err = client.CreateDomain(ctx, CreateDomainRequest{Name: "example.com"}); !errors.Is(err, ErrDomainNotAllowed) {
+		t.Fatalf("CreateDomain() error = %v", err)
+	}
+	if _, err = client.RestrictToDomains("other.example").GetDomain(ctx, "other.example"); !errors.Is(err, ErrDomainNotAllowed) {
+		t.Fatalf("narrowed GetDomain() error = %v", err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("sent = %q", sent)
+	}
+}
@@ -0,0 +1,122 @@
+package migadu
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sequenceDoer(t *testing.T, statuses []int, header http.Header, bodies *[]string) (HTTPDoer, *int) {
+	t.Helper()
+	calls := 0
+	return doerFunc(func(req *http.Request) (*http.Response, error) {
+		if bodies != nil && req.Body != nil {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			*bodies = append(*bodies, string(body))
+		}
+		status := statuses[calls]
+		calls++
+		return &http.Response{
+			StatusCode: status,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(`{"name":"example.com"}`)),
+		}, nil
+	}), &calls
+}
+
+func retryTestClient(doer HTTPDoer) *Client {
+	return &Client{
+		baseURL:     "https://api.test",
+		httpClient:  doer,
+		retry:       &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		credentials: StaticCredentials("admin@example.com", "secret"),
+	}
+}
+
+func TestRetryRecoversFromServerErrors(t *testing.T) {
+	doer, calls := sequenceDoer(t, []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK}, nil, nil)
+	domain, err := retryTestClient(doer).GetDomain(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("GetDomain() error = %v", err)
+	}
+	if domain.Name != "example.com" || *calls != 3 {
+		t.Fatalf("domain = %+v, calls = %d", domain, *calls)
+	}
+}
+
+func TestRetryStopsAfterMaxAttempts(t *testing.T) {
+	doer, calls := sequenceDoer(t, []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable}, nil, nil)
+	_, err := retryTestClient(doer).GetDomain(context.Background(), "example.com")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || *calls != 3 {
+		t.Fatalf("error = %v, calls = %d", err, *calls)
+	}
+}
+
+func TestRetrySkipsNonIdempotentAndClientErrors(t *testing.T) {
+	doer, calls := sequenceDoer(t, []int{http.StatusBadGateway}, nil, nil)
+	_, err := retryTestClient(doer).CreateDomain(context.Background(), CreateDomainRequest{Name: "example.com"})
+	if err == nil || *calls != 1 {
+		t.Fatalf("POST error = %v, calls = %d", err, *calls)
+	}
+	doer, calls = sequenceDoer(t, []int{http.StatusNotFound}, nil, nil)
+	_, err = retryTestClient(doer).GetDomain(context.Background(), "example.com")
+	if err == nil || *calls != 1 {
+		t.Fatalf("404 error = %v, calls = %d", err, *calls)
+	}
+}
+
+func TestRetryReplaysRequestBody(t *testing.T) {
+	var bodies []string
+	doer, _ := sequenceDoer(t, []int{http.StatusInternalServerError, http.StatusOK}, nil, &bodies)
+	description := "replayed"
+	_, err := retryTestClient(doer).UpdateDomain(context.Background(), "example.com", UpdateDomainRequest{Description: &description})
+	if err != nil {
+		t.Fatalf("UpdateDomain() error = %v", err)
+	}
+	if len(bodies) != 2 || bodies[0] != bodies[1] || !strings.Contains(bodies[1], "replayed") {
+		t.Fatalf("bodies = %q", bodies)
+	}
+}
+
+func TestRetryHonorsRetryAfterWithinDeadline(t *testing.T) {
+	doer, calls := sequenceDoer(t, []int{http.StatusTooManyRequests, http.StatusOK}, http.Header{"Retry-After": []string{"5"}}, nil)
+	client := retryTestClient(doer)
+	client.timeout = 50 * time.Millisecond
+	start := time.Now()
+	_, err := client.GetDomain(context.Background(), "example.com")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || *calls != 1 {
+		t.Fatalf("error = %v, calls = %d", err, *calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("waited %s for a Retry-After past the deadline", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{value: "2", want: 2 * time.Second, ok: true},
+		{value: now.Add(3 * time.Second).Format(http.TimeFormat), want: 3 * time.Second, ok: true},
+		{value: "", ok: false},
+		{value: "soon", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %v; want %s, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package migadu
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 )
 
@@ -12,6 +13,22 @@ type Rewrite struct {
 	LocalPartRule string   `json:"local_part_rule,omitempty"`
 	Name          string   `json:"name,omitempty"`
 	OrderNum      int      `json:"order_num,omitempty"`
+	// Extra holds JSON fields this version of the library does not model.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON keeps unknown fields in Extra.
+func (r *Rewrite) UnmarshalJSON(data []byte) error {
+	type plain Rewrite
+	extra, err := unmarshalWithExtra(data, (*plain)(r))
+	r.Extra = extra
+	return err
+}
+
+// MarshalJSON writes Extra back next to the known fields.
+func (r Rewrite) MarshalJSON() ([]byte, error) {
+	type plain Rewrite
+	return marshalWithExtra((*plain)(&r), r.Extra)
 }
 
 // CreateRewriteRequest contains fields accepted by the rewrite create endpoint.
@@ -30,6 +47,30 @@ type UpdateRewriteRequest struct {
 	OrderNum      *int      `json:"order_num,omitempty"`
 }
 
+// Validate checks the request locally. It returns a *ValidationError listing every invalid field.
+func (r CreateRewriteRequest) Validate() error {
+	var v validator
+	v.required("name", r.Name)
+	v.localPartRule("local_part_rule", r.LocalPartRule)
+	v.addresses("destinations", r.Destinations, true)
+	v.nonNegative("order_num", r.OrderNum)
+	return v.err()
+}
+
+// Validate checks the fields that are set. It returns a *ValidationError listing every invalid field.
+func (r UpdateRewriteRequest) Validate() error {
+	var v validator
+	v.optional("name", r.Name)
+	if r.LocalPartRule != nil {
+		v.localPartRule("local_part_rule", *r.LocalPartRule)
+	}
+	if r.Destinations != nil {
+		v.addresses("destinations", *r.Destinations, true)
+	}
+	v.nonNegative("order_num", r.OrderNum)
+	return v.err()
+}
+
 // ListRewrites lists all rewrites for a domain.
 // It returns the rewrites and any error encountered.
 func (c *Client) ListRewrites(ctx context.Context, domain string) ([]*Rewrite, error) {
@@ -45,13 +86,11 @@ func (c *Client) ListRewrites(ctx context.Context, domain string) ([]*Rewrite, e
 		return nil, err
 	}
 
-	resp, err := doRequest[struct {
-		Rewrites []*Rewrite `json:"rewrites,omitempty"`
-	}](c, ctx, req)
+	items, err := doListRequest[Rewrite](c, ctx, req, "rewrites")
 	if err != nil {
 		return nil, err
 	}
-	return resp.Rewrites, nil
+	return items, nil
 }
 
 // GetRewrite retrieves a single rewrite given its name.
@@ -74,6 +113,9 @@ func (c *Client) GetRewrite(ctx context.Context, domain, name string) (*Rewrite,
 
 // CreateRewrite creates a rewrite using all fields supported by the API.
 func (c *Client) CreateRewrite(ctx context.Context, domain string, rewrite CreateRewriteRequest) (*Rewrite, error) {
+	if err := c.validate(rewrite); err != nil {
+		return nil, err
+	}
 	builder, err := c.getDomainReqBuilder(domain)
 	if err != nil {
 		return nil, err
@@ -92,6 +134,9 @@ func (c *Client) CreateRewrite(ctx context.Context, domain string, rewrite Creat
 
 // UpdateRewrite updates only fields explicitly set on update.
 func (c *Client) UpdateRewrite(ctx context.Context, domain, name string, update UpdateRewriteRequest) (*Rewrite, error) {
+	if err := c.validate(update); err != nil {
+		return nil, err
+	}
 	builder, err := c.getDomainReqBuilder(domain)
 	if err != nil {
 		return nil, err
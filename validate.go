@@ -0,0 +1,200 @@
+package migadu
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ValidationError lists the fields of a request that Validate rejected.
+// Fields are named like the JSON fields of the request, as in APIError.FieldErrors,
+// so both can be shown next to the same inputs. It matches ErrValidation, and
+// ErrInvalidValue when an enumerated field holds an unknown value.
+type ValidationError struct {
+	FieldErrors []FieldError
+	causes      []error
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid request: " + formatFieldErrors(e.FieldErrors)
+}
+
+// Is reports whether target is ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.causes
+}
+
+// Messages returns the messages reported for field.
+func (e *ValidationError) Messages(field string) []string {
+	var messages []string
+	for _, fieldErr := range e.FieldErrors {
+		if fieldErr.Field == field {
+			messages = append(messages, fieldErr.Message)
+		}
+	}
+	return messages
+}
+
+// WithoutValidation sends Create and Update requests without calling their Validate
+// method first, leaving every check to the API.
+func WithoutValidation() Option {
+	return func(c *Client) {
+		c.skipValidation = true
+	}
+}
+
+// validate calls request.Validate unless validation is disabled.
+func (c *Client) validate(request interface{ Validate() error }) error {
+	if c.skipValidation {
+		return nil
+	}
+	return request.Validate()
+}
+
+// validator collects the problems of one request. Its messages follow the API's wording.
+type validator struct {
+	fieldErrors []FieldError
+	causes      []error
+}
+
+func (v *validator) add(field, message string) {
+	v.fieldErrors = append(v.fieldErrors, FieldError{Field: field, Message: message})
+}
+
+func (v *validator) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "can't be blank")
+		return false
+	}
+	return true
+}
+
+// optional checks a field that may be left unset but not set to blank.
+func (v *validator) optional(field string, value *string) {
+	if value != nil {
+		v.required(field, *value)
+	}
+}
+
+func (v *validator) localPart(field, value string) {
+	if v.required(field, value) && !validLocalPart(value, false) {
+		v.add(field, "is invalid")
+	}
+}
+
+// localPartRule checks a rewrite rule such as "demo-*", where * matches any characters.
+func (v *validator) localPartRule(field, value string) {
+	if v.required(field, value) && (!validLocalPart(value, true) || strings.Contains(value, "**")) {
+		v.add(field, "is invalid")
+	}
+}
+
+func (v *validator) domainName(field, value string) {
+	if v.required(field, value) && !validDomainName(value) {
+		v.add(field, "is invalid")
+	}
+}
+
+func (v *validator) address(field, value string) {
+	if value != "" && !validAddress(value) {
+		v.add(field, fmt.Sprintf("%q is not a valid address", value))
+	}
+}
+
+// addresses checks a list of addresses, which must not be empty when required.
+func (v *validator) addresses(field string, values []string, required bool) {
+	if required && len(values) == 0 {
+		v.add(field, "can't be blank")
+	}
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			v.add(field, "can't contain blank addresses")
+			continue
+		}
+		v.address(field, value)
+	}
+}
+
+func (v *validator) nonNegative(field string, value *int) {
+	if value != nil && *value < 0 {
+		v.add(field, "must be greater than or equal to 0")
+	}
+}
+
+// date checks an optional date. The zero Date is allowed and clears the field.
+func (v *validator) date(field string, value *Date) {
+	if value != nil && (value.Raw() != "" || !value.IsZero() && !value.IsValid()) {
+		v.add(field, fmt.Sprintf("%q is not a valid date", value.String()))
+	}
+}
+
+func (v *validator) enum(field string, value interface{ Validate() error }) {
+	err := value.Validate()
+	if err == nil {
+		return
+	}
+	v.causes = append(v.causes, err)
+	var enumErr *enumError
+	if errors.As(err, &enumErr) {
+		v.add(field, fmt.Sprintf("%q is not one of %s", enumErr.value, strings.Join(enumErr.known, ", ")))
+		return
+	}
+	v.add(field, err.Error())
+}
+
+func (v *validator) err() error {
+	if len(v.fieldErrors) == 0 {
+		return nil
+	}
+	return &ValidationError{FieldErrors: v.fieldErrors, causes: v.causes}
+}
+
+// validLocalPart accepts the dot-atom local parts Migadu allows, and * wildcards in rewrite rules.
+func validLocalPart(value string, wildcard bool) bool {
+	if value == "" || strings.HasPrefix(value, ".") || strings.HasSuffix(value, ".") || strings.Contains(value, "..") {
+		return false
+	}
+	for _, r := range value {
+		switch {
+		case r == '*':
+			if !wildcard {
+				return false
+			}
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+		case strings.ContainsRune("!#$%&'+/=?^_`{|}~.-", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// validDomainName accepts names of at least two labels of letters, digits and inner hyphens.
+func validDomainName(value string) bool {
+	value = strings.TrimSuffix(value, ".")
+	labels := strings.Split(value, ".")
+	if len(value) > 253 || len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func validAddress(value string) bool {
+	localPart, domain, found := strings.Cut(value, "@")
+	return found && validLocalPart(localPart, false) && validDomainName(domain)
+}
@@ -0,0 +1,146 @@
+package migadu
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateReportsEveryInvalidField(t *testing.T) {
+	blank := " "
+	rule := "demo-**"
+	february30 := NewDate(2024, 2, 30)
+	var unparsed Date
+	if err := unparsed.UnmarshalJSON([]byte(`"31/12/2025"`)); err != nil {
+		t.Fatal(err)
+	}
+	for name, test := range map[string]struct {
+		request interface{ Validate() error }
+		want    map[string][]string
+	}{
+		"alias": {
+			request: CreateAliasRequest{LocalPart: "bad local", Destinations: []string{"ok@example.com", "nope", ""}},
+			want: map[string][]string{
+				"local_part":   {"is invalid"},
+				"destinations": {`"nope" is not a valid address`, "can't contain blank addresses"},
+			},
+		},
+		"alias without destinations": {
+			request: CreateAliasRequest{LocalPart: "info"},
+			want:    map[string][]string{"destinations": {"can't be blank"}},
+		},
+		"forwarding": {
+			request: CreateForwardingRequest{Address: "someone@localhost"},
+			want:    map[string][]string{"address": {`"someone@localhost" is not a valid address`}},
+		},
+		"forwarding update": {
+			request: UpdateForwardingRequest{ExpiresOn: &february30},
+			want:    map[string][]string{"expires_on": {`"2024-02-30" is not a valid date`}},
+		},
+		"forwarding with expiry": {
+			request: CreateForwardingRequest{Address: "outside@example.net", ExpiresOn: &unparsed},
+			want:    map[string][]string{"expires_on": {`"31/12/2025" is not a valid date`}},
+		},
+		"alias update": {
+			request: UpdateAliasRequest{ExpiresOn: &february30},
+			want:    map[string][]string{"expires_on": {`"2024-02-30" is not a valid date`}},
+		},
+		"mailbox update": {
+			request: UpdateMailboxRequest{ExpiresOn: &february30, AutorespondExpiresOn: &unparsed},
+			want: map[string][]string{
+				"expires_on":             {`"2024-02-30" is not a valid date`},
+				"autorespond_expires_on": {`"31/12/2025" is not a valid date`},
+			},
+		},
+		"rewrite": {
+			request: CreateRewriteRequest{Name: "Team", LocalPartRule: "team.*.", Destinations: []string{"team@example.com"}},
+			want:    map[string][]string{"local_part_rule": {"is invalid"}},
+		},
+		"rewrite update": {
+			request: UpdateRewriteRequest{Name: &blank, LocalPartRule: &rule},
+			want:    map[string][]string{"name": {"can't be blank"}, "local_part_rule": {"is invalid"}},
+		},
+		"invitation": {
+			request: CreateMailboxRequest{LocalPart: "demo", Name: "Demo", PasswordMethod: PasswordMethodInvitation},
+			want:    map[string][]string{"password_recovery_email": {"can't be blank"}},
+		},
+		"mailbox": {
+			request: CreateMailboxRequest{LocalPart: ".demo", PasswordMethod: "sso", SpamAction: "quarantine"},
+			want: map[string][]string{
+				"local_part":      {"is invalid"},
+				"name":            {"can't be blank"},
+				"password_method": {`"sso" is not one of password, invitation`},
+				"spam_action":     {`"quarantine" is not one of folder, tag, drop`},
+			},
+		},
+		"domain": {
+			request: CreateDomainRequest{Name: "example", MailboxDefaultStorageLimit: new(int)},
+			want:    map[string][]string{"name": {"is invalid"}},
+		},
+	} {
+		err := test.request.Validate()
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || !IsValidation(err) {
+			t.Fatalf("%s: Validate() = %v", name, err)
+		}
+		got := map[string][]string{}
+		for _, fieldErr := range validationErr.FieldErrors {
+			got[fieldErr.Field] = append(got[fieldErr.Field], fieldErr.Message)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Fatalf("%s: field errors = %q, want %q", name, got, test.want)
+		}
+	}
+}
+
+func TestValidateAcceptsValidRequests(t *testing.T) {
+	destinations := []string{"team@example.com"}
+	expiresOn, noDate := NewDate(2025, 12, 31), Date{}
+	for _, request := range []interface{ Validate() error }{
+		CreateDomainRequest{Name: "bücher.example", CatchallDestinations: destinations},
+		UpdateDomainRequest{},
+		CreateMailboxRequest{LocalPart: "demo", Name: "Demo", Password: "secret"},
+		CreateMailboxRequest{LocalPart: "demo", Name: "Demo", PasswordMethod: PasswordMethodInvitation, PasswordRecoveryEmail: "demo@example.net"},
+		UpdateMailboxRequest{ExpiresOn: &expiresOn, AutorespondExpiresOn: &noDate},
+		CreateAliasRequest{LocalPart: "o'brien+info", Destinations: destinations},
+		UpdateAliasRequest{Destinations: &destinations},
+		CreateForwardingRequest{Address: "outside@example.net"},
+		UpdateForwardingRequest{ExpiresOn: &expiresOn},
+		CreateRewriteRequest{Name: "Team", LocalPartRule: "team-*", Destinations: destinations},
+		UpdateRewriteRequest{},
+		CreateIdentityRequest{LocalPart: "sales"},
+		UpdateIdentityRequest{},
+	} {
+		if err := request.Validate(); err != nil {
+			t.Fatalf("%T.Validate() = %v", request, err)
+		}
+	}
+}
+
+func TestClientValidatesRequestsUnlessDisabled(t *testing.T) {
+	sent := 0
+	client, err := NewWithOptions("admin@example.com", "secret",
+		WithBaseURL("https://api.test"),
+		WithHTTPClient(doerFunc(func(req *http.Request) (*http.Response, error) {
+			sent++
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	request := CreateAliasRequest{LocalPart: "info"}
+	_, err = client.CreateAlias(ctx, "example.com", request)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Messages("destinations")) != 1 || sent != 0 {
+		t.Fatalf("CreateAlias() error = %v, sent %d", err, sent)
+	}
+	if _, err = client.With(WithoutValidation()).CreateAlias(ctx, "example.com", request); err != nil || sent != 1 {
+		t.Fatalf("CreateAlias() without validation = %v, sent %d", err, sent)
+	}
+}
@@ -0,0 +1,249 @@
+package migadu
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultZoneTTL is the TTL of rendered records when ZoneOptions.TTL is zero.
+	DefaultZoneTTL = 3600
+	// defaultMXPriority is used for MX records without a priority.
+	defaultMXPriority = 10
+	// maxTXTString is the longest character-string a TXT record can hold (RFC 1035 section 3.3).
+	maxTXTString = 255
+)
+
+// ZoneOptions configures the DNS renderers.
+type ZoneOptions struct {
+	// Origin is the zone the records are rendered into. It defaults to the domain name.
+	// Names inside the origin are rendered relative to it, and names outside it absolutely.
+	Origin string
+	// TTL is the TTL of every record. Zero means DefaultZoneTTL.
+	TTL int
+}
+
+// ZoneRecord is a required record in the form DNS tooling expects.
+type ZoneRecord struct {
+	// Name is the absolute name with a trailing dot.
+	Name string `json:"name"`
+	// RelativeName is Name relative to the origin, "@" for the origin itself.
+	RelativeName string `json:"relative_name"`
+	Type         string `json:"type"`
+	TTL          int    `json:"ttl"`
+	Priority     *int   `json:"priority,omitempty"`
+	// Value is the record data: an absolute host name with a trailing dot for MX and CNAME
+	// records, and the unquoted text for TXT records.
+	Value string `json:"value"`
+}
+
+// ZoneRecords returns every required record with absolute and relative names resolved.
+func (r DomainRecords) ZoneRecords(opts ZoneOptions) []ZoneRecord {
+	origin := zoneOrigin(r, opts)
+	ttl := opts.TTL
+	if ttl == 0 {
+		ttl = DefaultZoneTTL
+	}
+	var zone []ZoneRecord
+	for _, record := range r.All() {
+		name := absoluteName(record.Name, r.DomainName)
+		zoneRecord := ZoneRecord{
+			Name:         name + ".",
+			RelativeName: relativeName(name, origin),
+			Type:         strings.ToUpper(record.Type),
+			TTL:          ttl,
+			Value:        record.Value,
+		}
+		switch zoneRecord.Type {
+		case "MX":
+			priority := defaultMXPriority
+			if record.Priority != nil {
+				priority = *record.Priority
+			}
+			zoneRecord.Priority = &priority
+			zoneRecord.Value = absoluteName(record.Value, "") + "."
+		case "CNAME":
+			zoneRecord.Value = absoluteName(record.Value, "") + "."
+		}
+		zone = append(zone, zoneRecord)
+	}
+	return zone
+}
+
+// BIND renders the records as a BIND zone file fragment, with names relative to the origin.
+func (r DomainRecords) BIND(opts ZoneOptions) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "; Migadu records for %s\n", r.DomainName)
+	for _, record := range r.ZoneRecords(opts) {
+		fmt.Fprintf(&b, "%s\t%d\tIN\t%s\t%s\n", record.RelativeName, record.TTL, record.Type, record.data())
+	}
+	return b.String()
+}
+
+// JSON renders the records as a JSON list of ZoneRecord.
+func (r DomainRecords) JSON(opts ZoneOptions) ([]byte, error) {
+	return json.MarshalIndent(r.ZoneRecords(opts), "", "  ")
+}
+
+// YAML renders the records as a YAML list with the same fields as JSON.
+func (r DomainRecords) YAML(opts ZoneOptions) string {
+	var b strings.Builder
+	for _, record := range r.ZoneRecords(opts) {
+		fmt.Fprintf(&b, "- name: %s\n", strconv.Quote(record.Name))
+		fmt.Fprintf(&b, "  relative_name: %s\n", strconv.Quote(record.RelativeName))
+		fmt.Fprintf(&b, "  type: %s\n", record.Type)
+		fmt.Fprintf(&b, "  ttl: %d\n", record.TTL)
+		if record.Priority != nil {
+			fmt.Fprintf(&b, "  priority: %d\n", *record.Priority)
+		}
+		// JSON string syntax is valid YAML and keeps quotes and backslashes intact.
+		value, _ := json.Marshal(record.Value)
+		fmt.Fprintf(&b, "  value: %s\n", value)
+	}
+	return b.String()
+}
+
+// NSUpdate renders the records as an nsupdate script of RFC 2136 update commands
+// that adds every record to the origin zone.
+func (r DomainRecords) NSUpdate(opts ZoneOptions) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "zone %s.\n", zoneOrigin(r, opts))
+	for _, record := range r.ZoneRecords(opts) {
+		fmt.Fprintf(&b, "update add %s %d IN %s %s\n", record.Name, record.TTL, record.Type, record.data())
+	}
+	b.WriteString("send\n")
+	return b.String()
+}
+
+// DNSUpdateMessage encodes an RFC 2136 UPDATE message that adds every record to the
+// origin zone. The message ID is zero; set the first two bytes before sending it,
+// and sign it with TSIG if the server requires it. A record whose name is outside the
+// origin is an error, since the server would reject the whole update with NOTZONE.
+func (r DomainRecords) DNSUpdateMessage(opts ZoneOptions) ([]byte, error) {
+	const (
+		opcodeUpdate = 5
+		typeCNAME    = 5
+		typeSOA      = 6
+		typeMX       = 15
+		typeTXT      = 16
+		classIN      = 1
+	)
+	origin := zoneOrigin(r, opts)
+	records := r.ZoneRecords(opts)
+	for _, record := range records {
+		// Only names outside the origin are rendered absolutely.
+		if strings.HasSuffix(record.RelativeName, ".") {
+			return nil, fmt.Errorf("encode %s: name is outside zone %s", record.Name, origin)
+		}
+	}
+	var msg bytes.Buffer
+	header := [6]uint16{0, opcodeUpdate << 11, 1, 0, uint16(len(records)), 0}
+	_ = binary.Write(&msg, binary.BigEndian, header)
+	if err := writeDNSName(&msg, origin); err != nil {
+		return nil, err
+	}
+	_ = binary.Write(&msg, binary.BigEndian, [2]uint16{typeSOA, classIN})
+	for _, record := range records {
+		var rrType uint16
+		var data bytes.Buffer
+		switch record.Type {
+		case "MX":
+			rrType = typeMX
+			_ = binary.Write(&data, binary.BigEndian, uint16(*record.Priority))
+			if err := writeDNSName(&data, record.Value); err != nil {
+				return nil, err
+			}
+		case "CNAME":
+			rrType = typeCNAME
+			if err := writeDNSName(&data, record.Value); err != nil {
+				return nil, err
+			}
+		case "TXT":
+			rrType = typeTXT
+			for _, chunk := range splitTXT(record.Value) {
+				data.WriteByte(byte(len(chunk)))
+				data.WriteString(chunk)
+			}
+		default:
+			return nil, fmt.Errorf("encode %s: unsupported record type %q", record.Name, record.Type)
+		}
+		if err := writeDNSName(&msg, record.Name); err != nil {
+			return nil, err
+		}
+		_ = binary.Write(&msg, binary.BigEndian, [2]uint16{rrType, classIN})
+		_ = binary.Write(&msg, binary.BigEndian, uint32(record.TTL))
+		_ = binary.Write(&msg, binary.BigEndian, uint16(data.Len()))
+		msg.Write(data.Bytes())
+	}
+	return msg.Bytes(), nil
+}
+
+// data returns the record data in zone file syntax.
+func (r ZoneRecord) data() string {
+	switch r.Type {
+	case "MX":
+		return fmt.Sprintf("%d %s", *r.Priority, r.Value)
+	case "TXT":
+		chunks := splitTXT(r.Value)
+		quoted := make([]string, len(chunks))
+		for i, chunk := range chunks {
+			quoted[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(chunk) + `"`
+		}
+		return strings.Join(quoted, " ")
+	}
+	return r.Value
+}
+
+// splitTXT splits a TXT value into character-strings of at most 255 bytes.
+func splitTXT(value string) []string {
+	if value == "" {
+		return []string{""}
+	}
+	var chunks []string
+	for len(value) > maxTXTString {
+		chunks = append(chunks, value[:maxTXTString])
+		value = value[maxTXTString:]
+	}
+	return append(chunks, value)
+}
+
+func zoneOrigin(r DomainRecords, opts ZoneOptions) string {
+	if opts.Origin != "" {
+		return normalizeHost(opts.Origin)
+	}
+	return normalizeHost(r.DomainName)
+}
+
+// relativeName returns name relative to origin, or absolutely with a trailing dot when it is outside origin.
+func relativeName(name, origin string) string {
+	lowerName, lowerOrigin := strings.ToLower(name), strings.ToLower(origin)
+	switch {
+	case lowerName == lowerOrigin:
+		return "@"
+	case origin != "" && strings.HasSuffix(lowerName, "."+lowerOrigin):
+		return name[:len(name)-len(origin)-1]
+	}
+	return name + "."
+}
+
+func writeDNSName(buf *bytes.Buffer, name string) error {
+	name = strings.TrimSuffix(name, ".")
+	if len(name) > 253 {
+		return fmt.Errorf("encode %s: name is longer than 253 bytes", name)
+	}
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if label == "" || len(label) > 63 {
+				return fmt.Errorf("encode %s: invalid label %q", name, label)
+			}
+			buf.WriteByte(byte(len(label)))
+			buf.WriteString(label)
+		}
+	}
+	buf.WriteByte(0)
+	return nil
+}
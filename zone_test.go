@@ -0,0 +1,102 @@
+package migadu
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func zoneTestRecords() DomainRecords {
+	priority := func(value int) *int { return &value }
+	return DomainRecords{
+		DomainName: "mail.example.com",
+		MXRecords: []DNSRecord{
+			{Name: "@", Type: "MX", Value: "aspmx1.migadu.com", Priority: priority(10)},
+			{Name: "mail.example.com", Type: "MX", Value: "aspmx2.migadu.com.", Priority: priority(20)},
+		},
+		SPF:  &DNSRecord{Name: "", Type: "TXT", Value: `v=spf1 include:spf.migadu.com -all`},
+		DKIM: []DNSRecord{{Name: "key1._domainkey", Type: "TXT", Value: "v=DKIM1; k=rsa; p=" + strings.Repeat("A", 300)}},
+		DMARC: &DNSRecord{
+			Name: "_dmarc.mail.example.com.", Type: "TXT", Value: `v=DMARC1; p=quarantine; rua="mailto:x"`,
+		},
+	}
+}
+
+func TestBIND(t *testing.T) {
+	got := zoneTestRecords().BIND(ZoneOptions{Origin: "example.com", TTL: 300})
+	want := "; Migadu records for mail.example.com\n" +
+		"mail\t300\tIN\tMX\t10 aspmx1.migadu.com.\n" +
+		"mail\t300\tIN\tMX\t20 aspmx2.migadu.com.\n" +
+		"mail\t300\tIN\tTXT\t\"v=spf1 include:spf.migadu.com -all\"\n" +
+		"key1._domainkey.mail\t300\tIN\tTXT\t\"v=DKIM1; k=rsa; p=" + strings.Repeat("A", 237) + "\" \"" + strings.Repeat("A", 63) + "\"\n" +
+		"_dmarc.mail\t300\tIN\tTXT\t\"v=DMARC1; p=quarantine; rua=\\\"mailto:x\\\"\"\n"
+	if got != want {
+		t.Fatalf("BIND() =\n%s\nwant\n%s", got, want)
+	}
+	if apex := zoneTestRecords().BIND(ZoneOptions{}); !strings.Contains(apex, "\n@\t3600\tIN\tMX\t10 aspmx1.migadu.com.\n") {
+		t.Fatalf("BIND() without origin =\n%s", apex)
+	}
+	if outside := zoneTestRecords().BIND(ZoneOptions{Origin: "other.net"}); !strings.Contains(outside, "\nmail.example.com.\t3600\tIN\tMX") {
+		t.Fatalf("BIND() outside origin =\n%s", outside)
+	}
+}
+
+func TestJSONAndYAML(t *testing.T) {
+	data, err := zoneTestRecords().JSON(ZoneOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []ZoneRecord
+	if err = json.Unmarshal(data, &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 || records[1].Name != "mail.example.com." || *records[1].Priority != 20 || records[3].RelativeName != "key1._domainkey" {
+		t.Fatalf("JSON() = %s", data)
+	}
+	yaml := zoneTestRecords().YAML(ZoneOptions{})
+	if !strings.Contains(yaml, "- name: \"mail.example.com.\"\n  relative_name: \"@\"\n  type: MX\n  ttl: 3600\n  priority: 10\n  value: \"aspmx1.migadu.com.\"\n") ||
+		!strings.Contains(yaml, `value: "v=DMARC1; p=quarantine; rua=\"mailto:x\""`) {
+		t.Fatalf("YAML() =\n%s", yaml)
+	}
+}
+
+func TestNSUpdate(t *testing.T) {
+	got := zoneTestRecords().NSUpdate(ZoneOptions{Origin: "example.com."})
+	if !strings.HasPrefix(got, "zone example.com.\nupdate add mail.example.com. 3600 IN MX 10 aspmx1.migadu.com.\n") || !strings.HasSuffix(got, "\nsend\n") {
+		t.Fatalf("NSUpdate() =\n%s", got)
+	}
+}
+
+func TestDNSUpdateMessage(t *testing.T) {
+	msg, err := zoneTestRecords().DNSUpdateMessage(ZoneOptions{Origin: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var header [6]uint16
+	if err = binary.Read(bytes.NewReader(msg), binary.BigEndian, &header); err != nil {
+		t.Fatal(err)
+	}
+	if header[1]>>11 != 5 || header[2] != 1 || header[3] != 0 || header[4] != 5 {
+		t.Fatalf("header = %v", header)
+	}
+	zone := "\x07example\x03com\x00\x00\x06\x00\x01"
+	if string(msg[12:12+len(zone)]) != zone {
+		t.Fatalf("zone section = %q", msg[12:12+len(zone)])
+	}
+	dkim := "\xffv=DKIM1; k=rsa; p=" + strings.Repeat("A", 237) + "\x3f" + strings.Repeat("A", 63)
+	if !bytes.Contains(msg, []byte(dkim)) {
+		t.Fatal("DKIM TXT record is not split into 255-byte strings")
+	}
+
+	records := zoneTestRecords()
+	records.DKIM[0].Type = "SRV"
+	if _, err = records.DNSUpdateMessage(ZoneOptions{}); err == nil {
+		t.Fatal("DNSUpdateMessage() accepted an unsupported record type")
+	}
+	_, err = zoneTestRecords().DNSUpdateMessage(ZoneOptions{Origin: "other.net"})
+	if err == nil || err.Error() != "encode mail.example.com.: name is outside zone other.net" {
+		t.Fatalf("DNSUpdateMessage() outside origin error = %v", err)
+	}
+}